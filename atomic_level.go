@@ -0,0 +1,164 @@
+package unilogger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AtomicLevel is the logging level that could be safely read and changed concurrently.
+// It could be shared between multiple loggers, so that a single level change propagates
+// to every logger holding it.
+// The AtomicLevel might have a parent level. Such level follows its parent level
+// until it is explicitly set using SetLevel method.
+// The level could be also temporarily elevated using ElevateFor method.
+type AtomicLevel struct {
+	level   int32
+	inherit int32
+	parent  *AtomicLevel
+
+	// elevation is the lowest of the active elevations levels increased by one.
+	// Zero value means that there is no active elevation.
+	elevation       int32
+	elevationsLock  sync.Mutex
+	elevations      map[uint64]levelElevation
+	lastElevationID uint64
+}
+
+// levelElevation is the active elevation along with its expiration timer.
+type levelElevation struct {
+	level Level
+	timer *time.Timer
+}
+
+// NewAtomicLevel creates new AtomicLevel set to 'level'.
+func NewAtomicLevel(level Level) *AtomicLevel {
+	return &AtomicLevel{level: int32(level)}
+}
+
+// NewChildLevel creates new AtomicLevel that follows the 'parent' level until it is explicitly set.
+func NewChildLevel(parent *AtomicLevel) *AtomicLevel {
+	return &AtomicLevel{level: int32(parent.Level()), inherit: 1, parent: parent}
+}
+
+// Level gets current level. If the level is elevated, it returns the lowest
+// of the elevation levels and the level set using SetLevel or inherited from the parent.
+func (a *AtomicLevel) Level() Level {
+	return a.elevated(a.BaseLevel())
+}
+
+// BaseLevel gets the level set using SetLevel or inherited from the parent, ignoring the elevations
+// of this level. It is the level that is used again after all the elevations end.
+func (a *AtomicLevel) BaseLevel() Level {
+	if a.parent != nil && atomic.LoadInt32(&a.inherit) == 1 {
+		return a.parent.Level()
+	}
+	return Level(atomic.LoadInt32(&a.level))
+}
+
+// elevated gets the lower of the 'level' and the active elevation.
+func (a *AtomicLevel) elevated(level Level) Level {
+	if elevation := atomic.LoadInt32(&a.elevation); elevation != 0 && Level(elevation-1).Severity() < level.Severity() {
+		level = Level(elevation - 1)
+	}
+	return level
+}
+
+// ElevateFor temporarily lowers the level threshold to the 'level' for the duration 'd'.
+// Afterwards the previous level is restored. The elevations might overlap - the level is then
+// the lowest of the active elevations. If the 'level' is higher than the current level
+// the elevation has no effect. Changing the level using SetLevel during the elevation
+// changes the level restored after the elevation ends.
+func (a *AtomicLevel) ElevateFor(level Level, d time.Duration) {
+	a.elevate(level, d)
+}
+
+// elevate lowers the level threshold to the 'level' for the duration 'd' and returns the id
+// of the elevation, which could be used to end it earlier using cancelElevation.
+func (a *AtomicLevel) elevate(level Level, d time.Duration) uint64 {
+	a.elevationsLock.Lock()
+	defer a.elevationsLock.Unlock()
+
+	if a.elevations == nil {
+		a.elevations = map[uint64]levelElevation{}
+	}
+	a.lastElevationID++
+	id := a.lastElevationID
+	timer := time.AfterFunc(d, func() {
+		a.elevationsLock.Lock()
+		defer a.elevationsLock.Unlock()
+
+		delete(a.elevations, id)
+		a.updateElevation()
+	})
+	a.elevations[id] = levelElevation{level: level, timer: timer}
+	a.updateElevation()
+	return id
+}
+
+// cancelElevation ends the elevation with the 'id' before it expires.
+// It does nothing if the elevation has already ended.
+func (a *AtomicLevel) cancelElevation(id uint64) {
+	a.elevationsLock.Lock()
+	defer a.elevationsLock.Unlock()
+
+	if elevation, ok := a.elevations[id]; ok {
+		elevation.timer.Stop()
+		delete(a.elevations, id)
+		a.updateElevation()
+	}
+}
+
+// IsElevated checks if there is any active elevation of the level.
+func (a *AtomicLevel) IsElevated() bool {
+	return atomic.LoadInt32(&a.elevation) != 0
+}
+
+// updateElevation sets the elevation to the lowest of the active elevations.
+// It must be called with the elevationsLock held.
+func (a *AtomicLevel) updateElevation() {
+	var elevation int32
+	for _, e := range a.elevations {
+		if elevation == 0 || e.level.Severity() < Level(elevation-1).Severity() {
+			elevation = int32(e.level) + 1
+		}
+	}
+	atomic.StoreInt32(&a.elevation, elevation)
+}
+
+// SetLevel atomically sets the level. If the level has a parent, it stops following the parent level.
+func (a *AtomicLevel) SetLevel(level Level) {
+	atomic.StoreInt32(&a.level, int32(level))
+	atomic.StoreInt32(&a.inherit, 0)
+}
+
+// Inherit makes the level follow its parent level again.
+// It doesn't change the level that has no parent.
+func (a *AtomicLevel) Inherit() {
+	if a.parent != nil {
+		atomic.StoreInt32(&a.inherit, 1)
+	}
+}
+
+// IsInherited checks if the level follows its parent level.
+func (a *AtomicLevel) IsInherited() bool {
+	return a.parent != nil && atomic.LoadInt32(&a.inherit) == 1
+}
+
+// Enabled checks if the messages at given 'level' are allowed by the current level.
+func (a *AtomicLevel) Enabled(level Level) bool {
+	return a.Level().IsAllowed(level)
+}
+
+// String implements fmt.Stringer interface.
+func (a *AtomicLevel) String() string {
+	return a.Level().String()
+}
+
+// WithAtomicLevel is the BasicLogger option that sets the 'level' shared with other loggers.
+// The level changes made using SetLevel method of any logger sharing the level affects all of them.
+func WithAtomicLevel(level *AtomicLevel) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
@@ -0,0 +1,141 @@
+package unilogger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAtomicLevel tests the AtomicLevel shared between the loggers.
+func TestAtomicLevel(t *testing.T) {
+	t.Run("Shared", func(t *testing.T) {
+		level := NewAtomicLevel(WARNING)
+
+		var first, second bytes.Buffer
+		firstLogger := NewBasicLogger(&first, "", 0, WithAtomicLevel(level))
+		secondLogger := NewBasicLogger(&second, "", 0, WithAtomicLevel(level))
+		assert.Equal(t, WARNING, secondLogger.GetLevel())
+
+		firstLogger.SetLevel(DEBUG)
+		assert.Equal(t, DEBUG, level.Level())
+		assert.Equal(t, "DEBUG", level.String())
+
+		secondLogger.Debug("debug")
+		assert.NotEmpty(t, second.String())
+
+		child := firstLogger.With("key", "value")
+		assert.Equal(t, level, child.AtomicLevel())
+
+		sub := firstLogger.SubLogger().(*BasicLogger)
+		sub.SetLevel(ERROR)
+		assert.Equal(t, DEBUG, firstLogger.GetLevel())
+		assert.Equal(t, ERROR, sub.GetLevel())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+
+		wg := &sync.WaitGroup{}
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					logger.Debug("message")
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					logger.SetLevel(Level(j % int(CRITICAL)))
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+// TestElevateFor tests the temporary level elevations.
+func TestElevateFor(t *testing.T) {
+	t.Run("Nested", func(t *testing.T) {
+		level := NewAtomicLevel(WARNING)
+		level.ElevateFor(DEBUG, 200*time.Millisecond)
+		level.ElevateFor(DEBUG3, 20*time.Millisecond)
+		level.ElevateFor(ERROR, time.Second)
+
+		assert.Equal(t, DEBUG3, level.Level())
+		assert.True(t, level.IsElevated())
+
+		waitFor(t, func() bool { return level.Level() == DEBUG })
+
+		level.SetLevel(INFO)
+		assert.Equal(t, DEBUG, level.Level())
+
+		waitFor(t, func() bool { return level.Level() == INFO })
+	})
+
+	t.Run("Inherited", func(t *testing.T) {
+		var buf bytes.Buffer
+		root := NewBasicLogger(&buf, "", 0)
+		db := root.Named("db")
+		pool := root.Named("db.pool")
+
+		db.ElevateFor(DEBUG2, 20*time.Millisecond)
+		assert.Equal(t, DEBUG2, pool.GetLevel())
+		assert.Equal(t, INFO, root.GetLevel())
+		assert.True(t, pool.IsLevelInherited())
+
+		pool.Debug2("elevated")
+		assert.Contains(t, buf.String(), "elevated")
+
+		waitFor(t, func() bool { return pool.GetLevel() == INFO })
+		assert.False(t, db.AtomicLevel().IsElevated())
+	})
+
+	t.Run("Wrapper", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+		wrapper := MustGetLoggerWrapper(logger)
+		wrapper.ElevateFor(DEBUG, 20*time.Millisecond)
+		assert.Equal(t, DEBUG, wrapper.GetLevel())
+
+		waitFor(t, func() bool { return wrapper.GetLevel() == INFO })
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+		registry := NewRegistry()
+		registry.MustRegister("logger", logger)
+
+		_, err := registry.SetLevelFor("logger", DEBUG, 200*time.Millisecond)
+		assert.NoError(t, err)
+		_, err = registry.SetLevelFor("logger", DEBUG3, 20*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Equal(t, DEBUG3, logger.GetLevel())
+
+		waitFor(t, func() bool { return logger.GetLevel() == DEBUG })
+		waitFor(t, func() bool { return logger.GetLevel() == INFO })
+	})
+
+	t.Run("RegistryElevated", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+		registry := NewRegistry()
+		registry.MustRegister("logger", logger)
+
+		logger.ElevateFor(DEBUG3, 20*time.Millisecond)
+		assert.Equal(t, DEBUG3, logger.GetLevel())
+		assert.Equal(t, INFO, logger.AtomicLevel().BaseLevel())
+
+		_, err := registry.SetLevelFor("logger", DEBUG, 50*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Equal(t, DEBUG3, logger.GetLevel())
+
+		waitFor(t, func() bool { return logger.GetLevel() == DEBUG })
+		waitFor(t, func() bool { return logger.GetLevel() == INFO })
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, INFO, logger.GetLevel())
+	})
+}
@@ -3,14 +3,22 @@ package unilogger
 import (
 	"fmt"
 	"io"
-	"log"
 	"os"
+	"path"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
 )
 
 var logSequenceID = uint64(0)
 
+// nextSequenceID returns the next message sequence id.
+func nextSequenceID() uint64 {
+	return atomic.AddUint64(&logSequenceID, 1)
+}
+
 /**
 
 Levels
@@ -33,36 +41,54 @@ const (
 	UNKNOWN
 )
 
+// Following syslog compatible levels are supported in addition to the basic levels.
+// The TRACE level is lower than DEBUG3, NOTICE is between INFO and WARNING,
+// ALERT and EMERGENCY are higher than CRITICAL.
+const (
+	TRACE Level = iota + UNKNOWN + 1
+	NOTICE
+	ALERT
+	EMERGENCY
+)
+
 // IsAllowed checks if the 'other' Level is allowed to be used in compare with 'l' Level.
+// The levels are compared by their severity.
 func (l Level) IsAllowed(other Level) bool {
-	return other >= l
+	return other.Severity() >= l.Severity()
 }
 
 var levelNames = map[Level]string{
-	DEBUG3:   "DEBUG3",
-	DEBUG2:   "DEBUG2",
-	DEBUG:    "DEBUG",
-	INFO:     "INFO",
-	WARNING:  "WARNING",
-	ERROR:    "ERROR",
-	CRITICAL: "CRITICAL",
-	PRINT:    "INFO",
+	TRACE:     "TRACE",
+	DEBUG3:    "DEBUG3",
+	DEBUG2:    "DEBUG2",
+	DEBUG:     "DEBUG",
+	INFO:      "INFO",
+	NOTICE:    "NOTICE",
+	WARNING:   "WARNING",
+	ERROR:     "ERROR",
+	CRITICAL:  "CRITICAL",
+	ALERT:     "ALERT",
+	EMERGENCY: "EMERGENCY",
+	PRINT:     "INFO",
 }
 
 func (l Level) String() string {
-	return levelNames[l]
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return loadLevelTable().names[l]
 }
 
-// ParseLevel parses level from string.
+// ParseLevel parses level from string. The level names are case insensitive
+// and might be one of the aliases i.e. 'warn', 'err', 'fatal' or a numeric value.
+// If the level is not recognized the function returns UNKNOWN level.
+// The parsing errors could be checked using Level.UnmarshalText method.
 func ParseLevel(level string) Level {
-	level = strings.ToUpper(level)
-
-	for l, name := range levelNames {
-		if name == level {
-			return l
-		}
+	l, err := parseLevelName(level)
+	if err != nil {
+		return UNKNOWN
 	}
-	return UNKNOWN
+	return l
 }
 
 /**
@@ -75,9 +101,115 @@ Message
 type Message struct {
 	id      uint64
 	level   Level
+	time    time.Time
 	fmt     *string
 	message *string
 	args    []interface{}
+	fields  []Field
+	logger  string
+	pc      uintptr
+	file    string
+	line    int
+}
+
+// NewMessage creates new Message with the next sequence id and the current time.
+// If the 'format' is nil the message text is created from the 'args' in the fmt.Sprint manner,
+// otherwise in the fmt.Sprintf manner.
+func NewMessage(level Level, format *string, args ...interface{}) *Message {
+	return &Message{
+		id:    nextSequenceID(),
+		level: level,
+		time:  time.Now(),
+		fmt:   format,
+		args:  args,
+	}
+}
+
+// ID returns the message sequence id.
+func (m *Message) ID() uint64 {
+	return m.id
+}
+
+// Level returns the message level.
+func (m *Message) Level() Level {
+	return m.level
+}
+
+// Format returns the message format. If the message was created without
+// the format, the function returns an empty string.
+func (m *Message) Format() string {
+	if m.fmt == nil {
+		return ""
+	}
+	return *m.fmt
+}
+
+// Args returns a copy of the message arguments.
+func (m *Message) Args() []interface{} {
+	args := make([]interface{}, len(m.args))
+	copy(args, m.args)
+	return args
+}
+
+// Fields returns a copy of the message fields.
+func (m *Message) Fields() []Field {
+	fields := make([]Field, len(m.fields))
+	copy(fields, m.fields)
+	return fields
+}
+
+// Logger returns the name of the logger that created the message.
+func (m *Message) Logger() string {
+	return m.logger
+}
+
+// Caller returns the file and line where the message was logged.
+// If the caller is not known the 'file' is empty.
+func (m *Message) Caller() (file string, line int) {
+	if m.file == "" && m.pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{m.pc}).Next()
+		m.file, m.line = frame.File, frame.Line
+	}
+	return m.file, m.line
+}
+
+// shortCaller returns the caller of the message in a 'dir/file.go:line' form.
+// If the caller is not known it returns an empty string.
+func (m *Message) shortCaller() string {
+	file, line := m.Caller()
+	if file == "" {
+		return ""
+	}
+	return path.Join(path.Base(path.Dir(file)), path.Base(file)) + ":" + strconv.Itoa(line)
+}
+
+// shortFile returns the base name of the 'file'.
+func shortFile(file string) string {
+	return path.Base(file)
+}
+
+// WithFields returns a copy of the message with the 'fields' added to the message fields.
+// If the field key already exists in the message its value is replaced.
+func (m *Message) WithFields(fields ...Field) *Message {
+	c := m.Clone()
+	c.fields = mergeFields(m.fields, fields)
+	return c
+}
+
+// Clone creates a copy of the message that could be safely passed to the asynchronous consumers.
+// The message text is rendered before copying, so that the clone is not affected
+// by the later changes of the arguments.
+func (m *Message) Clone() *Message {
+	msg := m.getMessage()
+	c := *m
+	c.message = &msg
+	if m.fmt != nil {
+		format := *m.fmt
+		c.fmt = &format
+	}
+	c.args = m.Args()
+	c.fields = m.Fields()
+	return &c
 }
 
 // Message prepares the string message based on the format and args private fields
@@ -100,12 +232,33 @@ func (m *Message) getMessage() string {
 	return *m.message
 }
 
+// Time returns the time when the message was created.
+func (m *Message) Time() time.Time {
+	return m.time
+}
+
 // String returns string that concantates:
-// id hash - 4 digits|time formatted in RFC339|level|message.
+// level|id hash - 4 digits: message or level|id hash - 4 digits|logger name: message
+// if the logger name is set.
+// The time of the message is not included. For the TextFormatter the message time is written
+// in the layout defined by the standard library logger flags.
+// If the message contains any fields they are written after the message in a 'key=value' form.
 // Implements fmt.Stringer interface.
 func (m *Message) String() string {
-	msg := fmt.Sprintf("%s|%04x: %s", m.level, m.id, m.getMessage())
-	return msg
+	var msg string
+	if m.logger == "" {
+		msg = fmt.Sprintf("%s|%04x: %s", m.level, m.id, m.getMessage())
+	} else {
+		msg = fmt.Sprintf("%s|%04x|%s: %s", m.level, m.id, m.logger, m.getMessage())
+	}
+	if len(m.fields) == 0 {
+		return msg
+	}
+	b := strings.Builder{}
+	b.WriteString(msg)
+	b.WriteRune(' ')
+	writeFields(&b, m.fields)
+	return b.String()
 }
 
 /**
@@ -128,22 +281,87 @@ BasicLogger
 // I.e. Having BasicLogger with level Set to WARNING, then there would be
 // no DEBUG and INFO logs (the hierarchy goes up only).
 type BasicLogger struct {
-	stdLogger   *log.Logger
-	level       Level
+	handler     Handler
+	level       *AtomicLevel
 	outputDepth int
+	fields      []Field
+	name        string
+	tree        *levelTree
+	vmodule     *VModule
+	filter      LevelFilter
+	clock       func() time.Time
+}
+
+// Option is the function that sets up the BasicLogger on its creation.
+type Option func(o *options)
+
+type options struct {
+	formatter func(out io.Writer) Formatter
+	handler   Handler
+	level     *AtomicLevel
+	name      string
+	vmodule   *VModule
+	filter    LevelFilter
+	clock     func() time.Time
+}
+
+// WithName is the BasicLogger option that sets the name of the logger.
+// The name is available for the formatters i.e. as the '{logger}' template placeholder.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithHandler is the BasicLogger option that sets the handler of the logger messages.
+// If the handler is set, the 'out', 'prefix', 'flags' and the formatter options
+// of the NewBasicLogger function are not used.
+func WithHandler(handler Handler) Option {
+	return func(o *options) {
+		o.handler = handler
+	}
 }
 
 var _ DebugLeveledLogger = &BasicLogger{}
 
 // NewBasicLogger creates new BasicLogger that shares common sequence id.
 // By default it uses DEBUG level. It can be changed later using SetLevel() method.
-// BasicLogger uses standard library *log.Logger for logging purpose.
-// The arguments used in this function are described in log.New() method.
-func NewBasicLogger(out io.Writer, prefix string, flags int) *BasicLogger {
+// BasicLogger passes the messages to the Handler. By default it uses the StdHandler
+// that writes the messages using standard library *log.Logger.
+// The arguments 'out', 'prefix' and 'flags' are described in log.New() method.
+// The 'options' allows to change the default behavior of the logger i.e. WithFormatter().
+func NewBasicLogger(out io.Writer, prefix string, flags int, opts ...Option) *BasicLogger {
+	o := &options{clock: time.Now}
+	for _, option := range opts {
+		option(o)
+	}
+
+	handler := o.handler
+	if handler == nil {
+		var formatter Formatter
+		if o.formatter != nil {
+			formatter = o.formatter(out)
+		}
+		handler = NewStdHandler(out, prefix, flags, formatter)
+	}
+	if o.name != "" {
+		handler = handler.WithName(o.name)
+	}
+
+	level := o.level
+	if level == nil {
+		level = NewAtomicLevel(INFO)
+	}
+
 	logger := &BasicLogger{
-		stdLogger:   log.New(out, prefix, flags),
-		level:       INFO,
+		handler:     handler,
+		level:       level,
 		outputDepth: 3,
+		name:        o.name,
+		tree:        newLevelTree(o.name, level),
+		vmodule:     o.vmodule,
+		filter:      o.filter,
+		clock:       o.clock,
 	}
 	return logger
 }
@@ -151,21 +369,106 @@ func NewBasicLogger(out io.Writer, prefix string, flags int) *BasicLogger {
 var _ SubLogger = &BasicLogger{}
 
 // SubLogger creates new sublogger for given logger.
+// The sublogger inherits the fields and follows the level of the given logger,
+// unless its level is explicitly set using SetLevel method.
+// The sublogger output depth is 4, so that it refers to the caller of the function calling its methods.
+// Thus the message caller and the vmodule rules of the sublogger are resolved one frame above its direct
+// caller. The depth could be changed using SetOutputDepth method.
 func (l *BasicLogger) SubLogger() LeveledLogger {
-	sub := &BasicLogger{
-		stdLogger:   l.stdLogger,
+	sub := l.clone()
+	sub.level = NewChildLevel(l.level)
+	sub.outputDepth = 4
+	return sub
+}
+
+// With creates a child logger that extends current logger fields with the provided
+// alternating key, value pairs. I.e.: With("user", "john", "id", 5).
+// The child logger shares the level with the given logger.
+// A key that is not a string or a key without the value is stored as '!BADKEY' field.
+// If the key already exists in the logger fields, its value is replaced in the child logger.
+func (l *BasicLogger) With(keyvals ...interface{}) *BasicLogger {
+	return l.withFields(fieldsFromKeyValues(keyvals...))
+}
+
+// WithFields creates a child logger that extends current logger fields with the provided
+// 'fields' map. The fields are added in the order sorted by their keys.
+// The child logger shares the level with the given logger.
+// If the key already exists in the logger fields, its value is replaced in the child logger.
+func (l *BasicLogger) WithFields(fields map[string]interface{}) *BasicLogger {
+	return l.withFields(fieldsFromMap(fields))
+}
+
+// Fields returns a copy of the logger fields.
+func (l *BasicLogger) Fields() []Field {
+	fields := make([]Field, len(l.fields))
+	copy(fields, l.fields)
+	return fields
+}
+
+// Handler returns the handler of the logger.
+func (l *BasicLogger) Handler() Handler {
+	return l.handler
+}
+
+var _ MessageLogger = &BasicLogger{}
+
+// LogMessage logs already created message 'm' if its level is enabled for the logger.
+// The message fields are extended with the logger fields.
+func (l *BasicLogger) LogMessage(m *Message) {
+	if l.filter != nil && !l.filter.IsAllowed(m.level) {
+		return
+	}
+	if !l.isCallerLevelEnabled(m.level, m.pc) || !l.handler.Enabled(m.level) {
+		return
+	}
+	l.handler.Handle(m)
+}
+
+func (l *BasicLogger) withFields(fields []Field) *BasicLogger {
+	child := l.clone()
+	child.fields = mergeFields(l.fields, fields)
+	child.handler = l.handler.WithFields(fields)
+	return child
+}
+
+func (l *BasicLogger) clone() *BasicLogger {
+	return &BasicLogger{
+		handler:     l.handler,
 		level:       l.level,
-		outputDepth: 4,
+		outputDepth: l.outputDepth,
+		fields:      l.fields,
+		name:        l.name,
+		tree:        l.tree,
+		vmodule:     l.vmodule,
+		filter:      l.filter,
+		clock:       l.clock,
 	}
-	return sub
 }
 
 var _ LevelSetter = &BasicLogger{}
 
 // SetLevel sets the level of logging for given Logger.
+// The level is changed atomically for all the loggers sharing the level.
 func (l *BasicLogger) SetLevel(level Level) {
 	l.Debugf("Setting log level to: '%s'", level)
-	l.level = level
+	l.level.SetLevel(level)
+}
+
+var _ LevelElevator = &BasicLogger{}
+
+// ElevateFor temporarily lowers the logger level threshold to the 'level' for the duration 'd'.
+// Afterwards the previous level is restored. For the overlapping elevations the lowest
+// of the active elevations levels is used. I.e. ElevateFor(DEBUG3, 10*time.Minute) enables
+// all the logs for ten minutes.
+func (l *BasicLogger) ElevateFor(level Level, d time.Duration) {
+	l.Debugf("Elevating log level to: '%s' for: %s", level, d)
+	l.level.ElevateFor(level, d)
+}
+
+// AtomicLevel gets the level used by the logger. It might be shared with other loggers
+// using WithAtomicLevel option.
+func (l *BasicLogger) AtomicLevel() *AtomicLevel {
+	return l.level
 }
 
 // SetOutputDepth set sthe output depth of the basic logger
@@ -181,7 +484,7 @@ func (l *BasicLogger) GetOutputDepth() int {
 
 // GetLevel gets current logger level.
 func (l *BasicLogger) GetLevel() Level {
-	return l.level
+	return l.level.Level()
 }
 
 // Debug3 logs a message with DEBUG level.
@@ -289,19 +592,48 @@ PRIVATE
 */
 
 func (l *BasicLogger) log(level Level, format *string, args ...interface{}) {
-	if !l.isLevelEnabled(level) {
+	if !l.isLevelEnabled(level) && (l.vmodule == nil || !l.vmodule.mayEnable(level)) {
 		return
 	}
+	if l.filter != nil && !l.filter.IsAllowed(level) {
+		return
+	}
+	if !l.handler.Enabled(level) {
+		return
+	}
+	// the output depth is relative to the log function caller frames.
+	var pc uintptr
+	var pcs [1]uintptr
+	if runtime.Callers(l.outputDepth, pcs[:]) > 0 {
+		pc = pcs[0]
+	}
+	if !l.isCallerLevelEnabled(level, pc) {
+		return
+	}
+
+	// the args are copied, so that the callers' variadic slices don't escape for the disabled logs.
 	msg := &Message{
-		id:    atomic.AddUint64(&logSequenceID, 1),
+		id:    nextSequenceID(),
 		level: level,
+		time:  l.clock(),
 		fmt:   format,
-		args:  args,
+		args:  append([]interface{}(nil), args...),
+		pc:    pc,
 	}
+	l.handler.Handle(msg)
+}
 
-	l.stdLogger.Output(l.outputDepth, msg.String())
+// isCallerLevelEnabled checks if the level is enabled for the call site with the program counter 'pc'.
+// If any of the vmodule rules matches the call site its level is used instead of the logger level.
+func (l *BasicLogger) isCallerLevelEnabled(level Level, pc uintptr) bool {
+	if l.vmodule != nil {
+		if callerLevel, ok := l.vmodule.LevelFor(pc); ok {
+			return callerLevel.IsAllowed(level)
+		}
+	}
+	return l.isLevelEnabled(level)
 }
 
 func (l *BasicLogger) isLevelEnabled(level Level) bool {
-	return level >= l.level
+	return l.level.Enabled(level)
 }
@@ -40,6 +40,38 @@ func TestMessage(t *testing.T) {
 			assert.Equal(t, fmt.Sprintf("%s|%04x: %s", message.level, message.id, message.getMessage()), str)
 		})
 	})
+
+	t.Run("Accessors", func(t *testing.T) {
+		format := "%s-%d"
+		message := NewMessage(WARNING, &format, "first", 2).WithFields(Field{Key: "key", Value: "value"})
+		assert.Equal(t, logSequenceID, message.ID())
+		assert.Equal(t, WARNING, message.Level())
+		assert.False(t, message.Time().IsZero())
+		assert.Equal(t, format, message.Format())
+		assert.Equal(t, []interface{}{"first", 2}, message.Args())
+		assert.Equal(t, []Field{{Key: "key", Value: "value"}}, message.Fields())
+		assert.Equal(t, "first-2", message.Message())
+
+		file, line := message.Caller()
+		assert.Empty(t, file)
+		assert.Zero(t, line)
+
+		assert.Empty(t, NewMessage(INFO, nil, "no format").Format())
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		args := []interface{}{"first", "second"}
+		message := NewMessage(INFO, nil, args...).WithFields(Field{Key: "key", Value: "value"})
+		clone := message.Clone()
+		assert.Equal(t, message.String(), clone.String())
+
+		args[0] = "changed"
+		assert.Equal(t, "firstsecond", clone.Message())
+		assert.Equal(t, []interface{}{"first", "second"}, clone.Args())
+
+		clone.fields[0].Value = "changed"
+		assert.Equal(t, "value", message.Fields()[0].Value)
+	})
 }
 
 // TestBasicLogger tests the basic logger functions.
@@ -49,12 +81,12 @@ func TestBasicLogger(t *testing.T) {
 		logger := NewBasicLogger(&buf, "", 0)
 		assert.NotNil(t, logger)
 		assert.IsType(t, &BasicLogger{}, logger)
-		assert.NotNil(t, logger.stdLogger)
-		assert.Equal(t, INFO, logger.level)
+		assert.NotNil(t, logger.handler)
+		assert.Equal(t, INFO, logger.GetLevel())
 
 		t.Run("SetLevel", func(t *testing.T) {
 			logger.SetLevel(ERROR)
-			assert.Equal(t, ERROR, logger.level)
+			assert.Equal(t, ERROR, logger.GetLevel())
 		})
 
 		args := []interface{}{"First", "Second"}
@@ -156,3 +188,49 @@ func prepareMessage(id uint64, level Level, fmt *string, args ...interface{}) *M
 func fmtMsg(msg *Message) string {
 	return fmt.Sprintf("%s\n", msg.String())
 }
+
+// TestBasicLoggerFields tests the structured fields of the BasicLogger.
+func TestBasicLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewBasicLogger(&buf, "", 0)
+
+	t.Run("With", func(t *testing.T) {
+		buf.Reset()
+		child := logger.With("user", "john", "id", 5)
+		child.Info("message")
+
+		assert.Equal(t, fmt.Sprintf("INFO|%04x: message user=john id=5\n", logSequenceID), buf.String())
+		assert.Empty(t, logger.Fields())
+	})
+
+	t.Run("WithFields", func(t *testing.T) {
+		buf.Reset()
+		child := logger.WithFields(map[string]interface{}{"b": "second value", "a": 1})
+		child.Info("message")
+
+		assert.Equal(t, fmt.Sprintf("INFO|%04x: message a=1 b=\"second value\"\n", logSequenceID), buf.String())
+	})
+
+	t.Run("Inherit", func(t *testing.T) {
+		buf.Reset()
+		parent := logger.With("service", "api", "id", 1)
+		child := parent.With("id", 2, "request", "abc")
+		child.Info("message")
+
+		assert.Equal(t, fmt.Sprintf("INFO|%04x: message service=api id=2 request=abc\n", logSequenceID), buf.String())
+		assert.Equal(t, []Field{{Key: "service", Value: "api"}, {Key: "id", Value: 1}}, parent.Fields())
+
+		buf.Reset()
+		sub := parent.SubLogger()
+		sub.Info("sub")
+		assert.Equal(t, fmt.Sprintf("INFO|%04x: sub service=api id=1\n", logSequenceID), buf.String())
+	})
+
+	t.Run("BadKey", func(t *testing.T) {
+		buf.Reset()
+		child := logger.With(1, "key", "value", "last")
+		child.Info("message")
+
+		assert.Equal(t, fmt.Sprintf("INFO|%04x: message !BADKEY=1 key=value !BADKEY=last\n", logSequenceID), buf.String())
+	})
+}
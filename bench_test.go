@@ -2,6 +2,7 @@ package unilogger
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strings"
 	"testing"
 )
@@ -39,3 +40,12 @@ func BenchmarkBuilder(b *testing.B) {
 	}
 	b.Log(v)
 }
+
+// BenchmarkVModuleDisabled benchmarks the disabled logs of the BasicLogger with the vmodule rules.
+func BenchmarkVModuleDisabled(b *testing.B) {
+	logger := NewBasicLogger(ioutil.Discard, "", 0, WithVModule(MustVModule("other/*=DEBUG")))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.Debug("disabled")
+	}
+}
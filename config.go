@@ -0,0 +1,594 @@
+package unilogger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Config is the declarative configuration of the logging pipeline, loadable from JSON.
+// It describes the sinks - the outputs with their formats and level filters,
+// and the loggers that write to them. I.e.:
+//	{
+//	  "sinks": {
+//	    "console": {"output": "stderr", "format": "console", "level": "info"},
+//	    "debug": {"output": "/var/log/app/debug.log", "format": "json", "max_level": "debug"}
+//	  },
+//	  "loggers": {
+//	    "app": {"level": "debug", "sinks": ["console", "debug"], "fields": {"service": "api"}},
+//	    "app.db": {"level": "debug2"}
+//	  }
+//	}
+type Config struct {
+	Sinks   map[string]SinkConfig   `json:"sinks"`
+	Loggers map[string]LoggerConfig `json:"loggers"`
+}
+
+// SinkConfig is the configuration of the logging sink.
+type SinkConfig struct {
+	// Output is the output destination: 'stdout', 'stderr' (default) or the file path.
+	// The file is created if it doesn't exist and the logs are appended to it.
+	Output string `json:"output"`
+	// Format is the output format, one of: 'text' (default), 'json', 'logfmt', 'console' or 'template'.
+	Format string `json:"format,omitempty"`
+	// Template is the TemplateFormatter template used by the 'template' format.
+	Template string `json:"template,omitempty"`
+	// Prefix is the line prefix used by the 'text' format.
+	Prefix string `json:"prefix,omitempty"`
+	// Flags are the comma separated standard logger flags used by the 'text' format:
+	// 'date', 'time', 'microseconds', 'longfile', 'shortfile', 'utc', 'msgprefix' or 'std' (default).
+	// The value 'none' disables all the flags.
+	Flags string `json:"flags,omitempty"`
+	// Level is the minimal level of the messages written to the sink.
+	Level *Level `json:"level,omitempty"`
+	// MaxLevel is the maximal level of the messages written to the sink.
+	MaxLevel *Level `json:"max_level,omitempty"`
+	// Levels is the exact set of the levels written to the sink. It cannot be used together
+	// with the Level and MaxLevel.
+	Levels []Level `json:"levels,omitempty"`
+}
+
+// LoggerConfig is the configuration of the named logger. The logger name might contain dots,
+// the logger is then created as a child of the closest configured ancestor, i.e. 'app.db' is
+// the child of the 'app' logger. The logger with an empty name is the root logger - the ancestor of all the loggers.
+type LoggerConfig struct {
+	// Level is the logger level. If not set the logger follows the level of its parent
+	// or uses the INFO level if it has no configured parent.
+	Level *Level `json:"level,omitempty"`
+	// Sinks are the names of the sinks the logger writes to. If not set the logger uses the sinks
+	// of its parent or all the defined sinks if it has no configured parent.
+	Sinks []string `json:"sinks,omitempty"`
+	// Fields are the logger fields, added to the ones inherited from the parent.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// LoadConfig decodes the JSON config from the reader 'r'. The unknown keys are not allowed.
+func LoadConfig(r io.Reader) (*Config, error) {
+	config := &Config{}
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+	return config, nil
+}
+
+// LoadConfigFile decodes the JSON config from the file at 'path'.
+func LoadConfigFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadConfig(f)
+}
+
+/**
+
+Pipeline
+
+*/
+
+// Pipeline is the set of the loggers and sinks built from the Config.
+// The configuration of the live loggers could be changed using the Apply method.
+type Pipeline struct {
+	lock       sync.RWMutex
+	loggers    map[string]*BasicLogger
+	targets    map[string]*pipelineTarget
+	config     Config
+	generation *sinkGeneration
+}
+
+// Logger gets the logger with the 'name'. If the logger is not configured, it is created
+// as a child of its closest configured ancestor. Returns nil if there is no such ancestor.
+func (p *Pipeline) Logger(name string) *BasicLogger {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if logger, ok := p.loggers[name]; ok {
+		return logger
+	}
+	if parent, ok := closestLogger(p.loggers, name); ok {
+		return parent.Named(strings.TrimPrefix(name, parent.Name()+"."))
+	}
+	return nil
+}
+
+// Loggers gets the configured loggers keyed by their names.
+func (p *Pipeline) Loggers() map[string]*BasicLogger {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	loggers := make(map[string]*BasicLogger, len(p.loggers))
+	for name, logger := range p.loggers {
+		loggers[name] = logger
+	}
+	return loggers
+}
+
+// Sink gets the sink with the 'name' or nil if it doesn't exist.
+func (p *Pipeline) Sink(name string) *Sink {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.generation.sinks[name]
+}
+
+// Config gets the currently applied config.
+func (p *Pipeline) Config() Config {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.config
+}
+
+// Close closes all the sinks. Returns the first error.
+func (p *Pipeline) Close() error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.generation.close()
+}
+
+// Sink is the logging output with its handler, built from the SinkConfig.
+type Sink struct {
+	name    string
+	handler Handler
+	out     io.Writer
+	closer  io.Closer
+}
+
+// Name gets the sink name.
+func (s *Sink) Name() string {
+	return s.name
+}
+
+// Handler gets the sink handler, including its level filter.
+func (s *Sink) Handler() Handler {
+	return s.handler
+}
+
+// Writer gets the sink output writer.
+func (s *Sink) Writer() io.Writer {
+	return s.out
+}
+
+// Close closes the sink output if it is a file. The standard outputs are not closed.
+func (s *Sink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// sinkGeneration is the set of the sinks built together. The loggers write to the sinks
+// holding the read lock, so that the sinks are closed only after the in-flight records are written.
+type sinkGeneration struct {
+	sync.RWMutex
+	sinks  map[string]*Sink
+	closed bool
+}
+
+func (g *sinkGeneration) close() error {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.closed {
+		return nil
+	}
+	g.closed = true
+
+	var err error
+	for _, sink := range g.sinks {
+		if closeErr := sink.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+/**
+
+Build
+
+*/
+
+// Build creates the sinks and the loggers described in the 'config'.
+// Returns error if the config is not valid or any of the outputs couldn't be opened.
+// In that case all the already opened outputs are closed.
+func Build(config Config) (*Pipeline, error) {
+	p := &Pipeline{
+		loggers:    map[string]*BasicLogger{},
+		targets:    map[string]*pipelineTarget{},
+		generation: &sinkGeneration{},
+	}
+	if err := p.Apply(config); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Apply applies the 'config' to the live loggers. The new sinks are built first and if the config
+// is not valid or any of the outputs couldn't be opened, the error is returned and the current
+// config keeps running. Otherwise the handlers of the loggers are atomically replaced, their levels
+// are set and the new loggers are created. The loggers removed from the config are reset to the defaults,
+// i.e. they follow the level of their parent. Afterwards the previous sinks are closed,
+// once all the in-flight records are written. The fields and sinks are applied to the loggers created
+// before, i.e. using Named or With methods, but the hierarchy of the existing loggers doesn't change.
+func (p *Pipeline) Apply(config Config) error {
+	generation, err := buildSinks(config.Sinks)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	names := make([]string, 0, len(config.Loggers)+len(p.loggers))
+	for name := range config.Loggers {
+		if name != strings.Trim(name, ".") {
+			generation.close()
+			return fmt.Errorf("invalid logger name: '%s'", name)
+		}
+		names = append(names, name)
+	}
+	for name := range p.loggers {
+		if _, ok := config.Loggers[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	// the sorted names ensure the parents are built before their children.
+	sort.Strings(names)
+
+	states := make(map[string]*pipelineState, len(names))
+	for _, name := range names {
+		handler, err := loggerHandler(config, generation, name)
+		if err != nil {
+			generation.close()
+			return err
+		}
+		states[name] = &pipelineState{handler: handler, generation: generation}
+	}
+
+	for _, name := range names {
+		logger, ok := p.loggers[name]
+		if !ok {
+			target := &pipelineTarget{}
+			target.state.Store(states[name])
+			p.targets[name] = target
+
+			if parent, ok := closestLogger(p.loggers, name); ok {
+				logger = parent.Named(strings.TrimPrefix(name, parent.Name()+"."))
+				logger.handler = (&pipelineHandler{target: target}).WithName(name)
+			} else {
+				logger = NewBasicLogger(nil, "", 0, WithName(name), WithHandler(&pipelineHandler{target: target}))
+			}
+			p.loggers[name] = logger
+		} else {
+			p.targets[name].state.Store(states[name])
+		}
+
+		switch level := config.Loggers[name].Level; {
+		case level != nil:
+			logger.level.SetLevel(*level)
+		case logger.level.parent != nil:
+			logger.level.Inherit()
+		default:
+			logger.level.SetLevel(INFO)
+		}
+	}
+
+	previous := p.generation
+	p.generation = generation
+	p.config = config
+	return previous.close()
+}
+
+// buildSinks builds the sinks from their configs. If there are no sinks, the default
+// text sink writing to the standard error output is used.
+func buildSinks(configs map[string]SinkConfig) (*sinkGeneration, error) {
+	generation := &sinkGeneration{sinks: map[string]*Sink{}}
+	for name, config := range configs {
+		sink, err := buildSink(name, config)
+		if err != nil {
+			generation.close()
+			return nil, err
+		}
+		generation.sinks[name] = sink
+	}
+	if len(generation.sinks) == 0 {
+		generation.sinks[""] = &Sink{handler: NewStdHandler(os.Stderr, "", log.LstdFlags, nil), out: os.Stderr}
+	}
+	return generation, nil
+}
+
+// loggerHandler creates the handler of the logger 'name' writing to its sinks with its fields.
+// The logger without the sinks uses the sinks of its closest configured ancestor
+// or all the sinks if there is no such ancestor. The fields of the ancestors are inherited.
+func loggerHandler(config Config, generation *sinkGeneration, name string) (Handler, error) {
+	var fields []Field
+	var sinks []string
+	hasSinks := false
+	for _, ancestor := range loggerAncestors(name) {
+		c, ok := config.Loggers[ancestor]
+		if !ok {
+			continue
+		}
+		for _, sinkName := range c.Sinks {
+			if _, ok := generation.sinks[sinkName]; !ok {
+				return nil, fmt.Errorf("logger: '%s' unknown sink: '%s'", ancestor, sinkName)
+			}
+		}
+		if len(c.Sinks) > 0 {
+			sinks, hasSinks = c.Sinks, true
+		}
+		fields = mergeFields(fields, fieldsFromMap(c.Fields))
+	}
+	if !hasSinks {
+		for sinkName := range generation.sinks {
+			sinks = append(sinks, sinkName)
+		}
+		sort.Strings(sinks)
+	}
+
+	handlers := make([]Handler, 0, len(sinks))
+	for _, sinkName := range sinks {
+		handlers = append(handlers, generation.sinks[sinkName].handler)
+	}
+	handler := joinHandlers(handlers)
+	if len(fields) > 0 {
+		handler = handler.WithFields(fields)
+	}
+	return handler, nil
+}
+
+// loggerAncestors gets the names of the root logger, the ancestors of the logger 'name'
+// and the 'name' itself, starting from the root.
+func loggerAncestors(name string) []string {
+	names := []string{""}
+	if name == "" {
+		return names
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] == '.' {
+			names = append(names, name[:i])
+		}
+	}
+	return append(names, name)
+}
+
+/**
+
+Pipeline handler
+
+*/
+
+// pipelineTarget holds the current state of the configured logger handler, replaced by Pipeline.Apply.
+type pipelineTarget struct {
+	state atomic.Value
+}
+
+type pipelineState struct {
+	handler    Handler
+	generation *sinkGeneration
+}
+
+// pipelineHandler is the Handler of the pipeline loggers that follows the state of its target.
+// The fields and the name of the derived handlers are applied to the current target handler.
+type pipelineHandler struct {
+	target *pipelineTarget
+	fields []Field
+	name   string
+	cache  atomic.Value
+}
+
+var _ Handler = &pipelineHandler{}
+
+var errPipelineClosed = errors.New("pipeline: already closed")
+
+type resolvedHandler struct {
+	state   *pipelineState
+	handler Handler
+}
+
+// resolve gets the current state of the target and the handler derived from its handler.
+func (h *pipelineHandler) resolve() (*pipelineState, Handler) {
+	state := h.target.state.Load().(*pipelineState)
+	if resolved, ok := h.cache.Load().(*resolvedHandler); ok && resolved.state == state {
+		return state, resolved.handler
+	}
+	handler := state.handler
+	if len(h.fields) > 0 {
+		handler = handler.WithFields(h.fields)
+	}
+	if h.name != "" {
+		handler = handler.WithName(h.name)
+	}
+	h.cache.Store(&resolvedHandler{state: state, handler: handler})
+	return state, handler
+}
+
+// Enabled implements Handler interface.
+func (h *pipelineHandler) Enabled(level Level) bool {
+	_, handler := h.resolve()
+	return handler.Enabled(level)
+}
+
+// Handle implements Handler interface. If the sinks were closed in the meantime by applying
+// the new config, the message is handled by the handler of the current state.
+// If the pipeline was closed, the message is dropped and the error is returned.
+func (h *pipelineHandler) Handle(m *Message) error {
+	var previous *pipelineState
+	for {
+		state, handler := h.resolve()
+		state.generation.RLock()
+		if state.generation.closed {
+			state.generation.RUnlock()
+			if state == previous {
+				return errPipelineClosed
+			}
+			previous = state
+			continue
+		}
+		err := handler.Handle(m)
+		state.generation.RUnlock()
+		return err
+	}
+}
+
+// WithFields implements Handler interface.
+func (h *pipelineHandler) WithFields(fields []Field) Handler {
+	return &pipelineHandler{target: h.target, fields: mergeFields(h.fields, fields), name: h.name}
+}
+
+// WithName implements Handler interface.
+func (h *pipelineHandler) WithName(name string) Handler {
+	return &pipelineHandler{target: h.target, fields: h.fields, name: name}
+}
+
+/**
+
+Sinks
+
+*/
+
+func buildSink(name string, config SinkConfig) (*Sink, error) {
+	filter, err := sinkFilter(config)
+	if err != nil {
+		return nil, fmt.Errorf("sink: '%s' %v", name, err)
+	}
+	flags, err := parseLogFlags(config.Flags)
+	if err != nil {
+		return nil, fmt.Errorf("sink: '%s' %v", name, err)
+	}
+	if strings.ToLower(config.Format) == "template" {
+		if _, err := NewTemplateFormatter(config.Template); err != nil {
+			return nil, fmt.Errorf("sink: '%s' %v", name, err)
+		}
+	} else if err := validateFormat(config.Format); err != nil {
+		return nil, fmt.Errorf("sink: '%s' %v", name, err)
+	}
+
+	out, err := openOutput(config.Output)
+	if err != nil {
+		return nil, fmt.Errorf("sink: '%s' %v", name, err)
+	}
+	sink := &Sink{name: name, out: out}
+	if out != os.Stdout && out != os.Stderr {
+		sink.closer, _ = out.(io.Closer)
+	}
+
+	var formatter Formatter
+	switch strings.ToLower(config.Format) {
+	case "json":
+		formatter = &JSONFormatter{}
+	case "logfmt":
+		formatter = &LogfmtFormatter{}
+	case "console":
+		formatter = NewConsoleFormatter(out)
+	case "template":
+		formatter = MustTemplateFormatter(config.Template)
+	}
+
+	sink.handler = NewStdHandler(out, config.Prefix, flags, formatter)
+	if filter != nil {
+		sink.handler = NewLevelFilterHandler(filter, sink.handler)
+	}
+	return sink, nil
+}
+
+// sinkFilter gets the level filter of the sink or nil if the sink accepts all levels.
+func sinkFilter(config SinkConfig) (LevelFilter, error) {
+	if len(config.Levels) > 0 {
+		if config.Level != nil || config.MaxLevel != nil {
+			return nil, fmt.Errorf("levels cannot be used together with level or max_level")
+		}
+		return NewLevelSet(config.Levels...), nil
+	}
+	switch {
+	case config.Level != nil && config.MaxLevel != nil:
+		if config.Level.Severity() > config.MaxLevel.Severity() {
+			return nil, fmt.Errorf("level: '%s' is higher than max_level: '%s'", config.Level, config.MaxLevel)
+		}
+		return NewLevelRange(*config.Level, *config.MaxLevel), nil
+	case config.Level != nil:
+		return *config.Level, nil
+	case config.MaxLevel != nil:
+		return NewLevelRange(Levels()[0], *config.MaxLevel), nil
+	}
+	return nil, nil
+}
+
+var logFlags = map[string]int{
+	"date":         log.Ldate,
+	"time":         log.Ltime,
+	"microseconds": log.Lmicroseconds,
+	"longfile":     log.Llongfile,
+	"shortfile":    log.Lshortfile,
+	"utc":          log.LUTC,
+	"msgprefix":    log.Lmsgprefix,
+	"std":          log.LstdFlags,
+	"none":         0,
+}
+
+// parseLogFlags parses the comma separated standard logger flags. An empty value is log.LstdFlags.
+func parseLogFlags(value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return log.LstdFlags, nil
+	}
+	var flags int
+	for _, name := range strings.Split(value, ",") {
+		flag, ok := logFlags[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return 0, fmt.Errorf("unknown flag: '%s'", name)
+		}
+		flags |= flag
+	}
+	return flags, nil
+}
+
+// closestLogger gets the logger of the closest ancestor of the 'name' logger.
+// The root logger with an empty name is the ancestor of all the other loggers.
+func closestLogger(loggers map[string]*BasicLogger, name string) (*BasicLogger, bool) {
+	if name == "" {
+		return nil, false
+	}
+	for i := strings.LastIndexByte(name, '.'); i > 0; i = strings.LastIndexByte(name[:i], '.') {
+		if logger, ok := loggers[name[:i]]; ok {
+			return logger, true
+		}
+	}
+	logger, ok := loggers[""]
+	return logger, ok
+}
+
+func joinHandlers(handlers []Handler) Handler {
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+	return NewMultiHandler(handlers...)
+}
@@ -0,0 +1,283 @@
+package unilogger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadConfig tests decoding the JSON config.
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(strings.NewReader(`{
+		"sinks": {"out": {"output": "stdout", "format": "json", "level": "warn", "max_level": "error"}},
+		"loggers": {"app": {"level": "debug", "sinks": ["out"], "fields": {"service": "api"}}}
+	}`))
+	require.NoError(t, err)
+
+	sink := config.Sinks["out"]
+	assert.Equal(t, "stdout", sink.Output)
+	assert.Equal(t, "json", sink.Format)
+	if assert.NotNil(t, sink.Level) && assert.NotNil(t, sink.MaxLevel) {
+		assert.Equal(t, WARNING, *sink.Level)
+		assert.Equal(t, ERROR, *sink.MaxLevel)
+	}
+
+	logger := config.Loggers["app"]
+	if assert.NotNil(t, logger.Level) {
+		assert.Equal(t, DEBUG, *logger.Level)
+	}
+	assert.Equal(t, []string{"out"}, logger.Sinks)
+	assert.Equal(t, map[string]interface{}{"service": "api"}, logger.Fields)
+
+	_, err = LoadConfig(strings.NewReader(`{"sinks": {"out": {"level": "verbose"}}}`))
+	assert.Error(t, err)
+
+	_, err = LoadConfig(strings.NewReader(`{"outputs": {}}`))
+	assert.Error(t, err)
+}
+
+// TestBuild tests building the logging pipeline from the config.
+func TestBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	debugPath := filepath.Join(dir, "debug.log")
+	mainPath := filepath.Join(dir, "main.log")
+	configPath := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(`{
+		"sinks": {
+			"main": {"output": "`+mainPath+`", "format": "template", "template": "{level} [{logger}] {msg} {fields}", "level": "info"},
+			"debug": {"output": "`+debugPath+`", "format": "logfmt", "max_level": "debug"}
+		},
+		"loggers": {
+			"app": {"level": "debug2", "sinks": ["main", "debug"], "fields": {"service": "api"}},
+			"app.db": {"level": "warning"},
+			"app.http": {"sinks": ["main"], "fields": {"component": "http"}}
+		}
+	}`), 0644))
+
+	config, err := LoadConfigFile(configPath)
+	require.NoError(t, err)
+
+	p, err := Build(*config)
+	require.NoError(t, err)
+
+	app := p.Logger("app")
+	require.NotNil(t, app)
+	assert.Equal(t, DEBUG2, app.GetLevel())
+	assert.Equal(t, WARNING, p.Logger("app.db").GetLevel())
+	assert.Equal(t, DEBUG2, p.Logger("app.http").GetLevel())
+	assert.Equal(t, "app.cache", p.Logger("app.cache").Name())
+	assert.Nil(t, p.Logger("other"))
+	assert.Len(t, p.Loggers(), 3)
+	assert.NotNil(t, p.Sink("main"))
+
+	app.Debug2("starting")
+	app.Info("started")
+	p.Logger("app.db").Info("skipped")
+	p.Logger("app.db").Error("failed")
+	p.Logger("app.http").Debug("request")
+	p.Logger("app.http").Info("listening")
+	require.NoError(t, p.Close())
+
+	main, err := ioutil.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, "INFO [app] started service=api\n"+
+		"ERROR [app.db] failed service=api\n"+
+		"INFO [app.http] listening service=api component=http\n", string(main))
+
+	debug, err := ioutil.ReadFile(debugPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(debug)), "\n")
+	if assert.Len(t, lines, 1) {
+		assert.Contains(t, lines[0], "level=DEBUG2")
+		assert.Contains(t, lines[0], "msg=starting")
+		assert.Contains(t, lines[0], "service=api")
+	}
+}
+
+// TestBuildRoot tests the root logger and the default sinks.
+func TestBuildRoot(t *testing.T) {
+	level := WARNING
+	p, err := Build(Config{Loggers: map[string]LoggerConfig{"": {Level: &level}, "db": {}}})
+	require.NoError(t, err)
+	defer p.Close()
+
+	assert.Equal(t, WARNING, p.Logger("").GetLevel())
+	assert.Equal(t, WARNING, p.Logger("db").GetLevel())
+	assert.Equal(t, "http.server", p.Logger("http.server").Name())
+	assert.Equal(t, os.Stderr, p.Sink("").Writer())
+}
+
+// TestBuildInvalid tests building the pipeline from the invalid configs.
+func TestBuildInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	info, debug := INFO, DEBUG
+	configs := map[string]Config{
+		"UnknownSink":    {Loggers: map[string]LoggerConfig{"app": {Sinks: []string{"missing"}}}},
+		"Format":         {Sinks: map[string]SinkConfig{"out": {Format: "xml"}}},
+		"Template":       {Sinks: map[string]SinkConfig{"out": {Format: "template", Template: "{unknown}"}}},
+		"Flags":          {Sinks: map[string]SinkConfig{"out": {Flags: "date,unknown"}}},
+		"Range":          {Sinks: map[string]SinkConfig{"out": {Level: &info, MaxLevel: &debug}}},
+		"LevelsAndLevel": {Sinks: map[string]SinkConfig{"out": {Level: &info, Levels: []Level{DEBUG}}}},
+		"Output":         {Sinks: map[string]SinkConfig{"out": {Output: filepath.Join(dir, "missing", "out.log")}}},
+		"LoggerName":     {Loggers: map[string]LoggerConfig{"app.": {}}},
+	}
+	for name, config := range configs {
+		_, err := Build(config)
+		assert.Error(t, err, name)
+	}
+}
+
+// TestPipelineApply tests applying the new config to the live loggers.
+func TestPipelineApply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	debug := DEBUG
+	p, err := Build(Config{
+		Sinks: map[string]SinkConfig{"out": {Output: first, Format: "template", Template: "{level} [{logger}] {msg} {fields}"}},
+		Loggers: map[string]LoggerConfig{
+			"app":    {Fields: map[string]interface{}{"version": 1}},
+			"app.db": {Level: &debug},
+		},
+	})
+	require.NoError(t, err)
+	defer p.Close()
+
+	app, db := p.Logger("app"), p.Logger("app.db")
+	pool := db.Named("pool").With("conn", 5)
+	pool.Debug("before")
+
+	t.Run("Valid", func(t *testing.T) {
+		warning := WARNING
+		require.NoError(t, p.Apply(Config{
+			Sinks:   map[string]SinkConfig{"out": {Output: second, Format: "logfmt", Flags: "none"}},
+			Loggers: map[string]LoggerConfig{"app": {Level: &warning, Fields: map[string]interface{}{"version": 2}}, "app.http": {}},
+		}))
+		assert.Equal(t, WARNING, app.GetLevel())
+		assert.True(t, db.IsLevelInherited())
+		assert.Equal(t, WARNING, db.GetLevel())
+		assert.Equal(t, db, p.Logger("app.db"))
+		assert.Equal(t, WARNING, p.Logger("app.http").GetLevel())
+
+		pool.Debug("skipped")
+		pool.Warning("after")
+
+		data, err := ioutil.ReadFile(first)
+		require.NoError(t, err)
+		assert.Equal(t, "DEBUG [app.db.pool] before version=1 conn=5\n", string(data))
+
+		data, err = ioutil.ReadFile(second)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "level=WARNING")
+		assert.Contains(t, string(data), "logger=app.db.pool msg=after")
+		assert.Contains(t, string(data), "version=2 conn=5")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		applied := p.Config()
+		err := p.Apply(Config{
+			Sinks:   map[string]SinkConfig{"out": {Output: first}},
+			Loggers: map[string]LoggerConfig{"app": {Level: &debug, Sinks: []string{"missing"}}},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, applied, p.Config())
+		assert.Equal(t, WARNING, app.GetLevel())
+		assert.Equal(t, second, p.Sink("out").Writer().(*os.File).Name())
+	})
+}
+
+// TestPipelineApplyInFlight tests that no records are lost while the config is applied concurrently.
+func TestPipelineApplyInFlight(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := func(i int) Config {
+		return Config{
+			Sinks:   map[string]SinkConfig{"out": {Output: filepath.Join(dir, fmt.Sprintf("%d.log", i)), Flags: "none"}},
+			Loggers: map[string]LoggerConfig{"app": {}},
+		}
+	}
+	p, err := Build(config(0))
+	require.NoError(t, err)
+
+	const writers, records = 4, 200
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			logger := p.Logger("app").With("writer", i)
+			for j := 0; j < records; j++ {
+				logger.Info("record")
+			}
+		}(i)
+	}
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, p.Apply(config(i)))
+	}
+	wg.Wait()
+	require.NoError(t, p.Close())
+
+	var lines int
+	for i := 0; i <= 10; i++ {
+		data, err := ioutil.ReadFile(filepath.Join(dir, fmt.Sprintf("%d.log", i)))
+		require.NoError(t, err)
+		lines += strings.Count(string(data), "\n")
+	}
+	assert.Equal(t, writers*records, lines)
+}
+
+// TestPipelineClosed tests that the records logged after the pipeline is closed are dropped.
+func TestPipelineClosed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	p, err := Build(Config{
+		Sinks:   map[string]SinkConfig{"out": {Output: path, Flags: "none"}},
+		Loggers: map[string]LoggerConfig{"app": {}},
+	})
+	require.NoError(t, err)
+
+	app := p.Logger("app")
+	app.Info("open")
+	require.NoError(t, p.Close())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		app.Info("closed")
+		app.With("key", "value").Error("closed")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("logging after close doesn't return")
+	}
+
+	handler := &pipelineHandler{target: p.targets["app"]}
+	assert.Equal(t, errPipelineClosed, handler.Handle(NewMessage(INFO, nil, "closed")))
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+	assert.Contains(t, string(data), "open")
+}
@@ -0,0 +1,126 @@
+package unilogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape codes used by the ConsoleFormatter.
+const (
+	colorReset  = "\x1b[0m"
+	colorDim    = "\x1b[2m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorCyan   = "\x1b[36m"
+	colorBold   = "\x1b[1m"
+)
+
+var levelColors = map[Level]string{
+	TRACE:     colorDim,
+	DEBUG3:    colorDim,
+	DEBUG2:    colorDim,
+	DEBUG:     colorBlue,
+	INFO:      colorGreen,
+	NOTICE:    colorCyan,
+	WARNING:   colorYellow,
+	ERROR:     colorRed,
+	CRITICAL:  colorBold + colorRed,
+	ALERT:     colorBold + colorRed,
+	EMERGENCY: colorBold + colorRed,
+	PRINT:     colorGreen,
+}
+
+// levelNameWidth is the width of the most common longest level name - 'CRITICAL'.
+// The longer level names are followed by a single space.
+const levelNameWidth = 8
+
+// ConsoleFormatter is the human friendly formatter used for the terminal output.
+// It writes the message in a 'time level [logger] message key=value' layout where
+// the columns are aligned, the level is colored and the fields are written after the message.
+// The colors are disabled if the 'Colors' field is false.
+type ConsoleFormatter struct {
+	// Colors defines if the output should contain ANSI color codes.
+	Colors bool
+	// Time defines the format of the message time. If its layout is not set, '15:04:05.000' is used.
+	Time TimeFormat
+}
+
+var _ Formatter = &ConsoleFormatter{}
+
+// NewConsoleFormatter creates new ConsoleFormatter for the 'out' writer.
+// The colors are enabled only if the 'out' is a terminal and the 'NO_COLOR' environment
+// variable is not set.
+func NewConsoleFormatter(out io.Writer) *ConsoleFormatter {
+	return &ConsoleFormatter{Colors: isTerminal(out) && os.Getenv("NO_COLOR") == ""}
+}
+
+// WithConsoleOutput is the BasicLogger option that sets the ConsoleFormatter
+// created for the logger output writer.
+func WithConsoleOutput() Option {
+	return func(o *options) {
+		o.formatter = func(out io.Writer) Formatter {
+			return NewConsoleFormatter(out)
+		}
+	}
+}
+
+// Format implements Formatter interface.
+func (c *ConsoleFormatter) Format(m *Message) ([]byte, error) {
+	format := c.Time
+	if format.Layout == "" {
+		format.Layout = "15:04:05.000"
+	}
+
+	b := strings.Builder{}
+	c.writeColored(&b, colorDim, format.Format(m.time))
+	b.WriteRune(' ')
+
+	level := m.level.String()
+	c.writeColored(&b, levelColors[m.level], level)
+	if len(level) < levelNameWidth {
+		b.WriteString(strings.Repeat(" ", levelNameWidth-len(level)))
+	}
+	b.WriteRune(' ')
+
+	if m.logger != "" {
+		c.writeColored(&b, colorBold, "["+m.logger+"]")
+		b.WriteRune(' ')
+	}
+	b.WriteString(m.getMessage())
+
+	for _, field := range m.fields {
+		b.WriteRune(' ')
+		c.writeColored(&b, colorCyan, field.Key+"=")
+		b.WriteString(quoteFieldValue(fmt.Sprint(field.Value)))
+	}
+
+	if caller := m.shortCaller(); caller != "" {
+		b.WriteRune(' ')
+		c.writeColored(&b, colorDim, caller)
+	}
+	return []byte(b.String()), nil
+}
+
+func (c *ConsoleFormatter) writeColored(b *strings.Builder, color, value string) {
+	if !c.Colors || color == "" {
+		b.WriteString(value)
+		return
+	}
+	b.WriteString(color)
+	b.WriteString(value)
+	b.WriteString(colorReset)
+}
+
+// isTerminal checks if the 'w' writer is a terminal. The other character devices
+// i.e. '/dev/null' are not treated as terminals.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return isTerminalFd(f.Fd())
+}
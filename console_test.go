@@ -0,0 +1,79 @@
+package unilogger
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConsoleFormatter tests the ConsoleFormatter.
+func TestConsoleFormatter(t *testing.T) {
+	msg := &Message{
+		level:  WARNING,
+		time:   time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC),
+		args:   []interface{}{"message"},
+		fields: []Field{{Key: "user", Value: "john doe"}},
+		logger: "db",
+	}
+
+	t.Run("NoColors", func(t *testing.T) {
+		formatted, err := (&ConsoleFormatter{}).Format(msg)
+		require.NoError(t, err)
+		assert.Equal(t, `12:30:00.000 WARNING  [db] message user="john doe"`, string(formatted))
+	})
+
+	t.Run("Colors", func(t *testing.T) {
+		formatted, err := (&ConsoleFormatter{Colors: true, Time: TimeFormat{Layout: time.Kitchen}}).Format(msg)
+		require.NoError(t, err)
+		assert.Equal(t, "\x1b[2m12:30PM\x1b[0m \x1b[33mWARNING\x1b[0m  \x1b[1m[db]\x1b[0m message \x1b[36muser=\x1b[0m\"john doe\"", string(formatted))
+	})
+
+	t.Run("TimeFormat", func(t *testing.T) {
+		precise := msg.Clone()
+		precise.time = time.Date(2019, 7, 1, 12, 30, 0, 123456789, time.FixedZone("CEST", 2*60*60))
+		formatter := &ConsoleFormatter{Time: TimeFormat{Layout: "15:04:05.000000", Precision: time.Millisecond, UTC: true}}
+		formatted, err := formatter.Format(precise)
+		require.NoError(t, err)
+		assert.Equal(t, `10:30:00.123000 WARNING  [db] message user="john doe"`, string(formatted))
+	})
+
+	t.Run("LongLevel", func(t *testing.T) {
+		long := msg.Clone()
+		long.level = EMERGENCY
+		formatted, err := (&ConsoleFormatter{}).Format(long)
+		require.NoError(t, err)
+		assert.Equal(t, `12:30:00.000 EMERGENCY [db] message user="john doe"`, string(formatted))
+	})
+
+	t.Run("Detection", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.False(t, NewConsoleFormatter(&buf).Colors)
+
+		f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		require.NoError(t, err)
+		defer f.Close()
+		assert.False(t, NewConsoleFormatter(f).Colors)
+
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		defer r.Close()
+		defer w.Close()
+		assert.False(t, NewConsoleFormatter(w).Colors)
+
+		os.Setenv("NO_COLOR", "1")
+		defer os.Unsetenv("NO_COLOR")
+		assert.False(t, NewConsoleFormatter(f).Colors)
+	})
+
+	t.Run("Logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewBasicLogger(&buf, "", 0, WithConsoleOutput())
+		logger.Error("failed")
+		assert.Contains(t, buf.String(), " ERROR    failed ")
+		assert.NotContains(t, buf.String(), "\x1b[")
+	})
+}
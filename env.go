@@ -0,0 +1,258 @@
+package unilogger
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Environment variables used by the Settings.LoadEnv and ConfigureFromEnv.
+const (
+	EnvLevel  = "UNILOGGER_LEVEL"
+	EnvFormat = "UNILOGGER_FORMAT"
+	EnvOutput = "UNILOGGER_OUTPUT"
+	EnvLevels = "UNILOGGER_LEVELS"
+)
+
+// Settings are the BasicLogger settings that could be read from the environment variables
+// (see LoadEnv) and the command line flags (see RegisterFlags).
+type Settings struct {
+	// Level is the root logger level.
+	Level Level
+	// Format is the output format, one of: 'text', 'json', 'logfmt' or 'console'.
+	Format string
+	// Output is the output destination: 'stdout', 'stderr' or the file path.
+	// The file is created if it doesn't exist and the logs are appended to it.
+	Output string
+	// Levels are the levels of the named loggers, i.e. 'db' or 'db.pool'.
+	Levels LevelOverrides
+}
+
+// NewSettings creates new Settings with the default values - INFO level, text format and stderr output.
+func NewSettings() *Settings {
+	return &Settings{Level: INFO, Format: "text", Output: "stderr", Levels: LevelOverrides{}}
+}
+
+// LoadEnv reads the settings from the environment variables:
+//	# UNILOGGER_LEVEL - root logger level, i.e. 'debug'
+//	# UNILOGGER_FORMAT - output format, i.e. 'json'
+//	# UNILOGGER_OUTPUT - 'stdout', 'stderr' or the file path
+//	# UNILOGGER_LEVELS - named logger levels, i.e. 'db=debug2,http=warning'
+// The variables that are not set or empty don't change the settings.
+// Returns error if any of the variables is not valid.
+func (s *Settings) LoadEnv() error {
+	if value := os.Getenv(EnvLevel); value != "" {
+		if err := s.Level.Set(value); err != nil {
+			return fmt.Errorf("%s: %v", EnvLevel, err)
+		}
+	}
+	if value := os.Getenv(EnvFormat); value != "" {
+		if err := validateFormat(value); err != nil {
+			return fmt.Errorf("%s: %v", EnvFormat, err)
+		}
+		s.Format = value
+	}
+	if value := os.Getenv(EnvOutput); value != "" {
+		s.Output = value
+	}
+	if value := os.Getenv(EnvLevels); value != "" {
+		if s.Levels == nil {
+			s.Levels = LevelOverrides{}
+		}
+		if err := s.Levels.Set(value); err != nil {
+			return fmt.Errorf("%s: %v", EnvLevels, err)
+		}
+	}
+	return nil
+}
+
+// RegisterFlags registers the settings command line flags in the 'fs' flag set:
+//	# -log-level - root logger level
+//	# -log-format - output format
+//	# -log-output - 'stdout', 'stderr' or the file path
+//	# -log-levels - named logger levels, might be repeated
+// The current settings values are used as the flag defaults, thus calling LoadEnv
+// before RegisterFlags allows to override the environment variables with the flags.
+func (s *Settings) RegisterFlags(fs *flag.FlagSet) {
+	if s.Levels == nil {
+		s.Levels = LevelOverrides{}
+	}
+	fs.Var(&s.Level, "log-level", "logging level, i.e. debug, info, warning")
+	fs.Var((*formatValue)(&s.Format), "log-format", "logging format: text, json, logfmt or console")
+	fs.StringVar(&s.Output, "log-output", s.Output, "logging output: stdout, stderr or the file path")
+	fs.Var(s.Levels, "log-levels", "named loggers levels, i.e. db=debug2,http=warning")
+}
+
+// NewLogger creates new BasicLogger with the settings. The 'opts' are applied after the settings options.
+// If the output is a file, it stays open for the lifetime of the process.
+func (s *Settings) NewLogger(opts ...Option) (*BasicLogger, error) {
+	if err := validateFormat(s.Format); err != nil {
+		return nil, err
+	}
+	out, err := openOutput(s.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []Option
+	if formatter := formatOption(s.Format); formatter != nil {
+		options = append(options, formatter)
+	}
+	logger := NewBasicLogger(out, "", log.LstdFlags, append(options, opts...)...)
+	s.Apply(logger)
+	return logger, nil
+}
+
+// Apply sets the levels of the 'logger' and its named descendants.
+// The output and format of the existing logger are not changed.
+func (s *Settings) Apply(logger *BasicLogger) {
+	logger.level.SetLevel(s.Level)
+	for _, name := range s.Levels.names() {
+		logger.Named(name).level.SetLevel(s.Levels[name])
+	}
+}
+
+// ConfigureFromEnv creates new BasicLogger configured using the environment variables (see Settings.LoadEnv).
+func ConfigureFromEnv(opts ...Option) (*BasicLogger, error) {
+	s := NewSettings()
+	if err := s.LoadEnv(); err != nil {
+		return nil, err
+	}
+	return s.NewLogger(opts...)
+}
+
+// RegisterFlags creates new Settings with the defaults read from the environment variables
+// and registers their command line flags in the 'fs' flag set. If 'fs' is nil, the flag.CommandLine is used.
+// The logger should be created using Settings.NewLogger after the flags are parsed.
+// Returns error if any of the environment variables is not valid.
+func RegisterFlags(fs *flag.FlagSet) (*Settings, error) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	s := NewSettings()
+	err := s.LoadEnv()
+	s.RegisterFlags(fs)
+	return s, err
+}
+
+/**
+
+LevelOverrides
+
+*/
+
+// LevelOverrides are the levels of the named loggers. It implements flag.Value
+// and parses the comma separated 'name=level' pairs, i.e.: 'db=debug2,http=warning'.
+type LevelOverrides map[string]Level
+
+var _ flag.Value = LevelOverrides{}
+
+// Set implements flag.Value interface. The parsed levels are added to the existing ones.
+// If any of the pairs is not valid, the overrides are not changed.
+func (o LevelOverrides) Set(value string) error {
+	parsed := map[string]Level{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		i := strings.IndexByte(pair, '=')
+		if i <= 0 {
+			return fmt.Errorf("invalid logger level: '%s'", pair)
+		}
+		name := strings.Trim(strings.TrimSpace(pair[:i]), ".")
+		if name == "" {
+			return fmt.Errorf("invalid logger level: '%s'", pair)
+		}
+		level, err := parseLevelName(pair[i+1:])
+		if err != nil {
+			return fmt.Errorf("invalid logger level: '%s': %v", pair, err)
+		}
+		parsed[name] = level
+	}
+	for name, level := range parsed {
+		o[name] = level
+	}
+	return nil
+}
+
+// String implements flag.Value interface.
+func (o LevelOverrides) String() string {
+	pairs := make([]string, 0, len(o))
+	for _, name := range o.names() {
+		level, _ := o[name].levelName()
+		pairs = append(pairs, name+"="+strings.ToLower(level))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// names gets the sorted logger names, so that the parents are set before their children.
+func (o LevelOverrides) names() []string {
+	names := make([]string, 0, len(o))
+	for name := range o {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/**
+
+Format and output
+
+*/
+
+// formatValue is the flag.Value that validates the format name.
+type formatValue string
+
+func (f *formatValue) Set(value string) error {
+	if err := validateFormat(value); err != nil {
+		return err
+	}
+	*f = formatValue(value)
+	return nil
+}
+
+func (f *formatValue) String() string {
+	return string(*f)
+}
+
+func validateFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "", "text", "json", "logfmt", "console":
+		return nil
+	}
+	return fmt.Errorf("unknown format: '%s'", format)
+}
+
+// formatOption gets the BasicLogger option for the 'format'. Returns nil for the text format.
+func formatOption(format string) Option {
+	switch strings.ToLower(format) {
+	case "json":
+		return WithJSONOutput()
+	case "logfmt":
+		return WithFormatter(&LogfmtFormatter{})
+	case "console":
+		return WithConsoleOutput()
+	}
+	return nil
+}
+
+// openOutput opens the output writer for the 'output' - 'stdout', 'stderr' or the file path.
+func openOutput(output string) (io.Writer, error) {
+	switch output {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	}
+	f, err := openAppend(output)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,139 @@
+package unilogger
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setEnv sets the environment variables and returns the function restoring the previous values.
+func setEnv(values map[string]string) func() {
+	previous := map[string]*string{}
+	for key, value := range values {
+		if prev, ok := os.LookupEnv(key); ok {
+			previous[key] = &prev
+		} else {
+			previous[key] = nil
+		}
+		os.Setenv(key, value)
+	}
+	return func() {
+		for key, prev := range previous {
+			if prev == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *prev)
+			}
+		}
+	}
+}
+
+// TestLevelOverrides tests parsing the named logger levels.
+func TestLevelOverrides(t *testing.T) {
+	o := LevelOverrides{}
+	require.NoError(t, o.Set("db=debug2, http=warning,"))
+	assert.Equal(t, LevelOverrides{"db": DEBUG2, "http": WARNING}, o)
+	assert.Equal(t, "db=debug2,http=warning", o.String())
+
+	require.NoError(t, o.Set("db.pool=error"))
+	assert.Len(t, o, 3)
+
+	for _, invalid := range []string{"db", "=debug", "db=verbose", "http=info,db"} {
+		assert.Error(t, o.Set(invalid), invalid)
+	}
+	assert.Len(t, o, 3)
+}
+
+// TestConfigureFromEnv tests creating the logger from the environment variables.
+func TestConfigureFromEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	t.Run("Valid", func(t *testing.T) {
+		defer setEnv(map[string]string{
+			EnvLevel:  "warn",
+			EnvFormat: "json",
+			EnvOutput: path,
+			EnvLevels: "db=debug2,http=error",
+		})()
+
+		logger, err := ConfigureFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, WARNING, logger.GetLevel())
+		assert.Equal(t, DEBUG2, logger.Named("db").GetLevel())
+		assert.Equal(t, DEBUG2, logger.Named("db.pool").GetLevel())
+		assert.Equal(t, ERROR, logger.Named("http").GetLevel())
+
+		logger.Info("skipped")
+		logger.Named("db").Debug2("query")
+
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(data), "\n"))
+		assert.Contains(t, string(data), `"level":"DEBUG2"`)
+		assert.Contains(t, string(data), `"logger":"db"`)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for key, value := range map[string]string{
+			EnvLevel:  "verbose",
+			EnvFormat: "xml",
+			EnvLevels: "db",
+			EnvOutput: filepath.Join(dir, "missing", "app.log"),
+		} {
+			restore := setEnv(map[string]string{key: value})
+			_, err := ConfigureFromEnv()
+			restore()
+			assert.Error(t, err, key)
+		}
+	})
+}
+
+// TestRegisterFlags tests the settings command line flags.
+func TestRegisterFlags(t *testing.T) {
+	defer setEnv(map[string]string{EnvLevel: "error", EnvFormat: "logfmt", EnvLevels: "db=debug"})()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(nopWriter{})
+	settings, err := RegisterFlags(fs)
+	require.NoError(t, err)
+	assert.Equal(t, ERROR, settings.Level)
+
+	require.NoError(t, fs.Parse([]string{"-log-level", "debug", "-log-output", "stdout", "-log-levels", "http=warning", "-log-levels", "db.pool=info"}))
+	assert.Equal(t, &Settings{
+		Level:  DEBUG,
+		Format: "logfmt",
+		Output: "stdout",
+		Levels: LevelOverrides{"db": DEBUG, "http": WARNING, "db.pool": INFO},
+	}, settings)
+
+	logger, err := settings.NewLogger()
+	require.NoError(t, err)
+	assert.Equal(t, DEBUG, logger.GetLevel())
+	assert.Equal(t, INFO, logger.Named("db.pool").GetLevel())
+
+	assert.Error(t, fs.Parse([]string{"-log-format", "xml"}))
+	assert.Error(t, fs.Parse([]string{"-log-level", "verbose"}))
+}
+
+// TestSettingsApply tests adjusting the levels of the existing logger.
+func TestSettingsApply(t *testing.T) {
+	logger := NewBasicLogger(nil, "", 0, WithHandler(newRecordingHandler()))
+	db := logger.Named("db")
+
+	settings := NewSettings()
+	settings.Level = WARNING
+	require.NoError(t, settings.Levels.Set("db=debug"))
+	settings.Apply(logger)
+
+	assert.Equal(t, WARNING, logger.GetLevel())
+	assert.Equal(t, DEBUG, db.GetLevel())
+}
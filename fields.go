@@ -0,0 +1,141 @@
+package unilogger
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// badKey is the key used for the values that were provided without a valid string key.
+const badKey = "!BADKEY"
+
+// Field is a structured key/value pair attached to the logging Message.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String implements fmt.Stringer interface. It returns the field in a 'key=value' form.
+func (f Field) String() string {
+	return f.Key + "=" + quoteFieldValue(fmt.Sprint(f.Value))
+}
+
+// reservedKeys are the keys of the message attributes written by the structured formatters.
+var reservedKeys = map[string]struct{}{
+	"time":   {},
+	"level":  {},
+	"seq":    {},
+	"logger": {},
+	"msg":    {},
+	"caller": {},
+}
+
+// fieldKey gets the key of the field written by the structured formatters.
+// The keys clashing with the reserved keys are prefixed with the 'fields.'.
+func fieldKey(key string) string {
+	if _, ok := reservedKeys[key]; ok {
+		return "fields." + key
+	}
+	return key
+}
+
+// fieldKeys gets the unique keys of the 'fields' written by the structured formatters.
+// The keys are got using fieldKey and the repeated ones i.e. '!BADKEY' are suffixed
+// with the number of their repetition, i.e. '!BADKEY.1'.
+func fieldKeys(fields []Field) []string {
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		key := fieldKey(field.Key)
+		for n := 1; containsString(keys[:i], key); n++ {
+			key = fieldKey(field.Key) + "." + strconv.Itoa(n)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldsFromKeyValues converts the alternating key, value arguments into the fields.
+// A key that is not a string, or a trailing key without a value is stored under the '!BADKEY' key.
+func fieldsFromKeyValues(keyvals ...interface{}) []Field {
+	fields := make([]Field, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i++ {
+		key, ok := keyvals[i].(string)
+		if !ok || i == len(keyvals)-1 {
+			fields = append(fields, Field{Key: badKey, Value: keyvals[i]})
+			continue
+		}
+		fields = append(fields, Field{Key: key, Value: keyvals[i+1]})
+		i++
+	}
+	return fields
+}
+
+// fieldsFromMap converts the map into the fields sorted by their keys.
+func fieldsFromMap(m map[string]interface{}) []Field {
+	fields := make([]Field, 0, len(m))
+	for k, v := range m {
+		fields = append(fields, Field{Key: k, Value: v})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+	return fields
+}
+
+// mergeFields returns a new slice containing 'parent' fields extended by the 'child' fields.
+// If the child field key already exists in the parent its value is replaced in place.
+func mergeFields(parent, child []Field) []Field {
+	merged := make([]Field, len(parent), len(parent)+len(child))
+	copy(merged, parent)
+
+	for _, field := range child {
+		var found bool
+		for i := range merged {
+			if merged[i].Key == field.Key && field.Key != badKey {
+				merged[i].Value = field.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, field)
+		}
+	}
+	return merged
+}
+
+// writeFields writes the fields in a 'key=value' form separated by spaces.
+func writeFields(b *strings.Builder, fields []Field) {
+	for i, field := range fields {
+		if i != 0 {
+			b.WriteRune(' ')
+		}
+		b.WriteString(field.String())
+	}
+}
+
+// quoteFieldValue quotes the value if it is empty or contains spaces, '=', quotes,
+// control or non printable characters.
+func quoteFieldValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
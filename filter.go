@@ -0,0 +1,98 @@
+package unilogger
+
+import (
+	"sort"
+	"strings"
+)
+
+// LevelFilter decides if the messages with given level are allowed to be logged.
+// The Level itself is a LevelFilter that allows the levels at or above it.
+// The filters could be used by the BasicLogger (see WithLevelFilter option),
+// the LoggerWrapper (see LoggerWrapper.WithLevelFilter) and the handlers (see NewLevelFilterHandler).
+type LevelFilter interface {
+	IsAllowed(level Level) bool
+}
+
+var (
+	_ LevelFilter = INFO
+	_ LevelFilter = LevelSet{}
+	_ LevelFilter = LevelRange{}
+)
+
+/**
+
+LevelSet
+
+*/
+
+// LevelSet is the LevelFilter that allows only the levels contained in the set.
+type LevelSet map[Level]struct{}
+
+// NewLevelSet creates new LevelSet that allows exactly the 'levels'.
+func NewLevelSet(levels ...Level) LevelSet {
+	set := make(LevelSet, len(levels))
+	for _, level := range levels {
+		set[level] = struct{}{}
+	}
+	return set
+}
+
+// IsAllowed implements LevelFilter interface.
+func (s LevelSet) IsAllowed(level Level) bool {
+	_, ok := s[level]
+	return ok
+}
+
+// String implements fmt.Stringer interface. The levels are sorted by their severity.
+func (s LevelSet) String() string {
+	levels := make([]Level, 0, len(s))
+	for level := range s {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Severity() < levels[j].Severity()
+	})
+	names := make([]string, len(levels))
+	for i, level := range levels {
+		names[i] = level.String()
+	}
+	return strings.Join(names, ",")
+}
+
+/**
+
+LevelRange
+
+*/
+
+// LevelRange is the LevelFilter that allows the levels with the severity between
+// the severities of the 'Min' and 'Max' levels, inclusive.
+type LevelRange struct {
+	Min, Max Level
+}
+
+// NewLevelRange creates new LevelRange that allows the levels from 'min' up to 'max'.
+func NewLevelRange(min, max Level) LevelRange {
+	return LevelRange{Min: min, Max: max}
+}
+
+// IsAllowed implements LevelFilter interface.
+func (r LevelRange) IsAllowed(level Level) bool {
+	severity := level.Severity()
+	return severity >= r.Min.Severity() && severity <= r.Max.Severity()
+}
+
+// String implements fmt.Stringer interface.
+func (r LevelRange) String() string {
+	return r.Min.String() + ".." + r.Max.String()
+}
+
+// WithLevelFilter is the BasicLogger option that sets the level 'filter'. The messages are logged
+// only if their level is enabled by the logger level and is allowed by the 'filter', i.e.
+// NewLevelRange(DEBUG2, DEBUG) logs only the DEBUG2 and DEBUG messages if the logger level is DEBUG2 or lower.
+// The filter is shared by the sub loggers.
+func WithLevelFilter(filter LevelFilter) Option {
+	return func(o *options) {
+		o.filter = filter
+	}
+}
@@ -0,0 +1,134 @@
+package unilogger
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLevelFilters tests the LevelSet and LevelRange filters.
+func TestLevelFilters(t *testing.T) {
+	t.Run("Set", func(t *testing.T) {
+		set := NewLevelSet(WARNING, DEBUG2)
+		assert.True(t, set.IsAllowed(WARNING))
+		assert.True(t, set.IsAllowed(DEBUG2))
+		assert.False(t, set.IsAllowed(ERROR))
+		assert.False(t, set.IsAllowed(DEBUG))
+		assert.Equal(t, "DEBUG2,WARNING", set.String())
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		r := NewLevelRange(DEBUG2, DEBUG)
+		assert.True(t, r.IsAllowed(DEBUG2))
+		assert.True(t, r.IsAllowed(DEBUG))
+		assert.False(t, r.IsAllowed(DEBUG3))
+		assert.False(t, r.IsAllowed(INFO))
+		assert.Equal(t, "DEBUG2..DEBUG", r.String())
+
+		r = NewLevelRange(INFO, WARNING)
+		assert.True(t, r.IsAllowed(NOTICE))
+		assert.False(t, r.IsAllowed(PRINT))
+	})
+
+	t.Run("Threshold", func(t *testing.T) {
+		var filter LevelFilter = WARNING
+		assert.True(t, filter.IsAllowed(ERROR))
+		assert.False(t, filter.IsAllowed(INFO))
+	})
+}
+
+// TestLevelFilterHandler tests routing the messages to the separate handlers using the level filters.
+func TestLevelFilterHandler(t *testing.T) {
+	debug, rest := newRecordingHandler(), newRecordingHandler()
+	handler := NewMultiHandler(
+		NewLevelFilterHandler(NewLevelRange(DEBUG3, DEBUG), debug),
+		NewLevelHandler(INFO, rest),
+	)
+	logger := NewBasicLogger(nil, "", 0, WithHandler(handler))
+	logger.SetLevel(DEBUG2)
+
+	logger.Debug3("skipped")
+	logger.Debug2("debug2")
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Error("error")
+
+	if assert.Len(t, *debug.messages, 2) {
+		assert.Equal(t, "debug2", (*debug.messages)[0].Message())
+		assert.Equal(t, "debug", (*debug.messages)[1].Message())
+	}
+	if assert.Len(t, *rest.messages, 2) {
+		assert.Equal(t, "info", (*rest.messages)[0].Message())
+		assert.Equal(t, "error", (*rest.messages)[1].Message())
+	}
+}
+
+// TestBasicLoggerLevelFilter tests the BasicLogger with the level filter.
+func TestBasicLoggerLevelFilter(t *testing.T) {
+	handler := newRecordingHandler()
+	logger := NewBasicLogger(nil, "", 0, WithHandler(handler), WithLevelFilter(NewLevelSet(DEBUG2, WARNING)))
+	logger.SetLevel(DEBUG3)
+
+	logger.Debug3("debug3")
+	logger.Debug2("debug2")
+	logger.Info("info")
+	logger.Warning("warning")
+	logger.Error("error")
+	logger.LogMessage(NewMessage(ERROR, nil, "error"))
+	logger.SubLogger().Warning("sub")
+
+	var messages []string
+	for _, m := range *handler.messages {
+		messages = append(messages, m.Message())
+	}
+	assert.Equal(t, []string{"debug2", "warning", "sub"}, messages)
+}
+
+// TestLoggerWrapperLevelFilter tests the LoggerWrapper with the level filter.
+func TestLoggerWrapperLevelFilter(t *testing.T) {
+	var buf bytes.Buffer
+	wrapper := MustGetLoggerWrapper(log.New(&buf, "", 0))
+	filtered := wrapper.WithLevelFilter(NewLevelSet(DEBUG, ERROR))
+
+	filtered.Debugf("%s", "debug")
+	filtered.Info("info")
+	filtered.Warningln("warning")
+	filtered.Error("error")
+	filtered.Print("print")
+	assert.Equal(t, "DEBUG: debug\nERROR: error\n", buf.String())
+
+	buf.Reset()
+	wrapper.Info("info")
+	assert.Equal(t, "INFO: info\n", buf.String())
+
+	t.Run("LogMessage", func(t *testing.T) {
+		var buf bytes.Buffer
+		basic := NewBasicLogger(&buf, "", 0)
+		basic.SetLevel(DEBUG3)
+		filtered := MustGetLoggerWrapper(basic).WithLevelFilter(NewLevelRange(DEBUG3, DEBUG2))
+
+		filtered.LogMessage(NewMessage(INFO, nil, "info"))
+		assert.Empty(t, buf.String())
+
+		filtered.LogMessage(NewMessage(DEBUG2, nil, "debug2"))
+		assert.Contains(t, buf.String(), "DEBUG2|")
+	})
+
+	t.Run("LeveledLogger", func(t *testing.T) {
+		var buf bytes.Buffer
+		basic := NewBasicLogger(&buf, "", 0)
+		basic.SetLevel(DEBUG3)
+		filtered := MustGetLoggerWrapper(unwrappedLogger{basic}).WithLevelFilter(NewLevelSet(DEBUG2))
+
+		// the DEBUG2 message is logged using the Debug method of the wrapped logger.
+		filtered.LogMessage(NewMessage(DEBUG2, nil, "debug2"))
+		assert.Contains(t, buf.String(), "DEBUG|")
+	})
+}
+
+// unwrappedLogger hides the MessageLogger implementation of the BasicLogger.
+type unwrappedLogger struct {
+	LeveledLogger
+}
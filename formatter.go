@@ -0,0 +1,266 @@
+package unilogger
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter is the interface that formats the logging Message into the bytes written by the BasicLogger.
+// The formatted record should not contain the trailing new line - it is added by the logger.
+type Formatter interface {
+	Format(m *Message) ([]byte, error)
+}
+
+// WithFormatter is the BasicLogger option that sets the formatter for the logger messages
+// written by the default StdHandler. By default BasicLogger uses TextFormatter.
+// The formatters other than TextFormatter are responsible for the whole log line,
+// thus the 'prefix' and 'flags' of the logger are not used.
+func WithFormatter(formatter Formatter) Option {
+	return func(o *options) {
+		o.formatter = func(io.Writer) Formatter {
+			return formatter
+		}
+	}
+}
+
+/**
+
+TextFormatter
+
+*/
+
+// TextFormatter is the default BasicLogger formatter. It formats the message
+// in a 'LEVEL|id: message key=value' layout - the same as the Message.String method.
+// The message time is written by the StdHandler in front of the message, in the layout
+// defined by the log.Ldate, log.Ltime, log.Lmicroseconds and log.LUTC flags.
+type TextFormatter struct{}
+
+var _ Formatter = &TextFormatter{}
+
+// Format implements Formatter interface.
+func (t *TextFormatter) Format(m *Message) ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+/**
+
+TemplateFormatter
+
+*/
+
+// TemplateFormatter is the formatter that writes the message using the template with
+// the placeholders in a '{name}' or '{name:argument}' form. Supported placeholders:
+//	# {time} - message time formatted using the 'Time' format, the argument is a time layout name
+//	  i.e. 'RFC3339', 'Kitchen' or a Go time layout that overrides the 'Time' layout
+//	# {level} - message level name
+//	# {seq} - message sequence id written as 4 hex digits
+//	# {logger} - name of the logger
+//	# {msg} - message text
+//	# {fields} - message fields in a 'key=value' form
+//	# {caller} - file and line where the message was logged
+// The argument for all placeholders except {time} is the minimal width of the value. The value
+// of the placeholder ending the template is not padded. The literal text, including the spaces
+// in front of an empty placeholder, is always written as it is.
+// The braces could be escaped by doubling them: '{{' and '}}'.
+// I.e.: '{time:RFC3339} {level:5} [{logger}] {msg} {fields}'.
+type TemplateFormatter struct {
+	// Time defines the format of the message time. If it is not set, the time is formatted
+	// using the RFC3339 layout.
+	Time TimeFormat
+
+	parts []templatePart
+}
+
+var _ Formatter = &TemplateFormatter{}
+
+// NewTemplateFormatter parses the 'template' and creates new TemplateFormatter.
+// The function returns error if the template contains unknown placeholders or unclosed braces.
+func NewTemplateFormatter(template string) (*TemplateFormatter, error) {
+	parts, err := parseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateFormatter{parts: parts}, nil
+}
+
+// MustTemplateFormatter creates new TemplateFormatter for given 'template'.
+// The function panics if the template is not valid.
+func MustTemplateFormatter(template string) *TemplateFormatter {
+	t, err := NewTemplateFormatter(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Format implements Formatter interface.
+func (t *TemplateFormatter) Format(m *Message) ([]byte, error) {
+	b := strings.Builder{}
+	for i, part := range t.parts {
+		var value string
+		switch part.kind {
+		case templateLiteral:
+			b.WriteString(part.literal)
+			continue
+		case templateTime:
+			format := t.Time
+			if part.layout != "" {
+				format.Layout = part.layout
+			} else if format == (TimeFormat{}) {
+				format.Layout = time.RFC3339
+			}
+			value = format.Format(m.time)
+		case templateLevel:
+			value = m.level.String()
+		case templateSeq:
+			value = fmt.Sprintf("%04x", m.id)
+		case templateLogger:
+			value = m.logger
+		case templateMsg:
+			value = m.getMessage()
+		case templateFields:
+			fb := strings.Builder{}
+			writeFields(&fb, m.fields)
+			value = fb.String()
+		case templateCaller:
+			value = m.shortCaller()
+		}
+		b.WriteString(value)
+		// the placeholder ending the template is not padded, as the padding would only add trailing spaces.
+		if i == len(t.parts)-1 {
+			continue
+		}
+		for n := len(value); n < part.width; n++ {
+			b.WriteRune(' ')
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+type templatePartKind int
+
+const (
+	templateLiteral templatePartKind = iota
+	templateTime
+	templateLevel
+	templateSeq
+	templateLogger
+	templateMsg
+	templateFields
+	templateCaller
+)
+
+var templatePlaceholders = map[string]templatePartKind{
+	"time":   templateTime,
+	"level":  templateLevel,
+	"seq":    templateSeq,
+	"logger": templateLogger,
+	"msg":    templateMsg,
+	"fields": templateFields,
+	"caller": templateCaller,
+}
+
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RFC822":      time.RFC822,
+	"RFC1123":     time.RFC1123,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+type templatePart struct {
+	kind    templatePartKind
+	literal string
+	layout  string
+	width   int
+}
+
+func parseTemplate(template string) ([]templatePart, error) {
+	var (
+		parts   []templatePart
+		literal strings.Builder
+	)
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			parts = append(parts, templatePart{kind: templateLiteral, literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		switch c {
+		case '{':
+			if i+1 < len(template) && template[i+1] == '{' {
+				literal.WriteByte('{')
+				i++
+				continue
+			}
+			end := strings.IndexByte(template[i:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed placeholder at position: %d in template: '%s'", i, template)
+			}
+			part, err := parsePlaceholder(template[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			flushLiteral()
+			parts = append(parts, part)
+			i += end
+		case '}':
+			if i+1 < len(template) && template[i+1] == '}' {
+				literal.WriteByte('}')
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("unexpected '}' at position: %d in template: '%s'", i, template)
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flushLiteral()
+	return parts, nil
+}
+
+func parsePlaceholder(placeholder string) (templatePart, error) {
+	name, argument := placeholder, ""
+	if i := strings.IndexByte(placeholder, ':'); i != -1 {
+		name, argument = placeholder[:i], placeholder[i+1:]
+	}
+
+	kind, ok := templatePlaceholders[name]
+	if !ok {
+		return templatePart{}, fmt.Errorf("unknown template placeholder: '%s'", name)
+	}
+	part := templatePart{kind: kind}
+
+	if kind == templateTime {
+		if layout, ok := timeLayouts[argument]; ok {
+			part.layout = layout
+		} else {
+			part.layout = argument
+		}
+		return part, nil
+	}
+
+	if argument != "" {
+		width, err := strconv.Atoi(argument)
+		if err != nil || width < 0 {
+			return templatePart{}, fmt.Errorf("invalid template placeholder width: '%s'", placeholder)
+		}
+		part.width = width
+	}
+	return part, nil
+}
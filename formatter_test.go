@@ -0,0 +1,111 @@
+package unilogger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingFormatter struct{}
+
+func (f *failingFormatter) Format(m *Message) ([]byte, error) {
+	return nil, errors.New("failed")
+}
+
+// TestTemplateFormatter tests the TemplateFormatter.
+func TestTemplateFormatter(t *testing.T) {
+	format := "%s-%s"
+	msg := &Message{
+		id:     0x1f,
+		level:  INFO,
+		time:   time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC),
+		fmt:    &format,
+		args:   []interface{}{"first", "second"},
+		fields: []Field{{Key: "user", Value: "john"}},
+		logger: "db",
+		file:   "uni-logger/basic.go",
+		line:   10,
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		formatter, err := NewTemplateFormatter("{time:RFC3339} {level:7}[{logger}] {seq} {msg} {fields} {{{caller}}}")
+		require.NoError(t, err)
+
+		formatted, err := formatter.Format(msg)
+		require.NoError(t, err)
+		assert.Equal(t, "2019-07-01T12:30:00Z INFO   [db] 001f first-second user=john {uni-logger/basic.go:10}", string(formatted))
+	})
+
+	t.Run("TimeLayout", func(t *testing.T) {
+		formatted, err := MustTemplateFormatter("{time:15:04} {time:Kitchen} {msg}").Format(msg)
+		require.NoError(t, err)
+		assert.Equal(t, "12:30 12:30PM first-second", string(formatted))
+	})
+
+	t.Run("TimeFormat", func(t *testing.T) {
+		formatter := MustTemplateFormatter("{time} {time:15:04:05.000} {msg}")
+		formatter.Time = TimeFormat{Precision: time.Millisecond, UTC: true}
+
+		c := *msg
+		c.time = time.Date(2019, 7, 1, 14, 30, 0, 123456789, time.FixedZone("CEST", 2*60*60))
+		formatted, err := formatter.Format(&c)
+		require.NoError(t, err)
+		assert.Equal(t, "2019-07-01T12:30:00.123Z 12:30:00.123 first-second", string(formatted))
+	})
+
+	t.Run("TrailingSpaces", func(t *testing.T) {
+		c := *msg
+		text := "spaces  "
+		c.message = &text
+
+		formatted, err := MustTemplateFormatter("{level:8}{msg}").Format(&c)
+		require.NoError(t, err)
+		assert.Equal(t, "INFO    spaces  ", string(formatted))
+
+		formatted, err = MustTemplateFormatter("[{msg}] ").Format(&c)
+		require.NoError(t, err)
+		assert.Equal(t, "[spaces  ] ", string(formatted))
+
+		formatted, err = MustTemplateFormatter("{msg} {level:8}").Format(&c)
+		require.NoError(t, err)
+		assert.Equal(t, "spaces   INFO", string(formatted))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		templates := []string{"{unknown}", "{msg", "msg}", "{level:x}", "{level:-1}"}
+		for _, template := range templates {
+			_, err := NewTemplateFormatter(template)
+			assert.Error(t, err, template)
+		}
+		assert.Panics(t, func() { MustTemplateFormatter("{unknown}") })
+	})
+}
+
+// TestWithFormatter tests the BasicLogger with the custom formatters.
+func TestWithFormatter(t *testing.T) {
+	t.Run("Template", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewBasicLogger(&buf, "prefix ", 0, WithName("api"), WithFormatter(MustTemplateFormatter("{level:8}[{logger}] {msg} {fields}")))
+		logger.With("id", 1).Warning("message")
+		assert.Equal(t, "WARNING [api] message id=1\n", buf.String())
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewBasicLogger(&buf, "prefix ", 0, WithFormatter(&TextFormatter{}))
+		logger.Info("message")
+		assert.Equal(t, fmt.Sprintf("prefix INFO|%04x: message\n", logSequenceID), buf.String())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewBasicLogger(&buf, "", 0, WithFormatter(&failingFormatter{}))
+		logger.Info("message")
+		assert.Equal(t, fmt.Sprintf("INFO|%04x: message !FORMAT_ERROR=\"failed\"\n", logSequenceID), buf.String())
+	})
+}
@@ -0,0 +1,259 @@
+package unilogger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+)
+
+// Handler is the interface that handles the logging messages created by the BasicLogger.
+// The handlers could be composed into pipelines, where each stage filters, enriches, encodes
+// or writes the messages and passes them to the next handler.
+type Handler interface {
+	// Enabled reports whether the handler handles the messages at given level.
+	Enabled(level Level) bool
+	// Handle handles the message. It is called only if the Enabled returns true for the message level.
+	Handle(m *Message) error
+	// WithFields returns new handler that adds the 'fields' to each handled message.
+	WithFields(fields []Field) Handler
+	// WithName returns new handler that sets the logger 'name' for each handled message.
+	WithName(name string) Handler
+}
+
+/**
+
+StdHandler
+
+*/
+
+// StdHandler is the Handler that formats the messages using the Formatter and writes them
+// using the standard library *log.Logger. It is the default handler of the BasicLogger.
+type StdHandler struct {
+	stdLogger  *log.Logger
+	formatter  Formatter
+	timeFormat TimeFormat
+	fileFlags  int
+	flags      int
+	msgPrefix  string
+	fields     []Field
+	name       string
+}
+
+var _ Handler = &StdHandler{}
+
+// NewStdHandler creates new StdHandler that writes the messages to the 'out' writer.
+// The arguments 'out', 'prefix' and 'flags' are described in log.New() method.
+// The 'formatter' formats the messages, if nil the TextFormatter is used.
+// The formatters other than TextFormatter are responsible for the whole log line,
+// thus the 'prefix' and 'flags' are not used for them.
+func NewStdHandler(out io.Writer, prefix string, flags int, formatter Formatter) *StdHandler {
+	if formatter == nil {
+		formatter = &TextFormatter{}
+	}
+	if _, ok := formatter.(*TextFormatter); !ok {
+		prefix, flags = "", 0
+	}
+
+	h := &StdHandler{formatter: formatter, flags: flags}
+	// the time is written from the message time captured by the logger clock and the caller file
+	// is resolved from the message, as the standard logger is not able to find the caller frame
+	// for the messages passed through the handlers.
+	h.timeFormat = stdTimeFormat(flags)
+	h.fileFlags = flags & (log.Lshortfile | log.Llongfile)
+	flags &^= log.Ldate | log.Ltime | log.Lmicroseconds | log.LUTC | log.Lshortfile | log.Llongfile
+	if (h.timeFormat.Layout != "" || h.fileFlags != 0) && flags&log.Lmsgprefix != 0 {
+		h.msgPrefix, prefix = prefix, ""
+		flags &^= log.Lmsgprefix
+	}
+	h.stdLogger = log.New(out, prefix, flags)
+	return h
+}
+
+// stdTimeFormat gets the TimeFormat matching the time header of the standard logger 'flags'.
+// The layout is empty if the flags don't contain the date nor the time.
+func stdTimeFormat(flags int) TimeFormat {
+	var layout string
+	if flags&log.Ldate != 0 {
+		layout = "2006/01/02 "
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		layout += "15:04:05"
+		if flags&log.Lmicroseconds != 0 {
+			layout += ".000000"
+		}
+		layout += " "
+	}
+	return TimeFormat{Layout: layout, UTC: flags&log.LUTC != 0}
+}
+
+// Enabled implements Handler interface. The StdHandler handles messages at all levels.
+func (h *StdHandler) Enabled(level Level) bool {
+	return true
+}
+
+// Handle implements Handler interface. The handler fields and name are applied
+// to the shallow copy of the message, so that the 'm' is not changed.
+func (h *StdHandler) Handle(m *Message) error {
+	if len(h.fields) != 0 || h.name != "" {
+		c := *m
+		if len(h.fields) != 0 {
+			c.fields = mergeFields(h.fields, m.fields)
+		}
+		if h.name != "" {
+			c.logger = h.name
+		}
+		m = &c
+	}
+
+	formatted, err := h.formatter.Format(m)
+	if err != nil {
+		formatted = []byte(fmt.Sprintf("%s !FORMAT_ERROR=%q", m.String(), err.Error()))
+	}
+
+	if h.timeFormat.Layout == "" && h.fileFlags == 0 {
+		return h.stdLogger.Output(0, string(formatted))
+	}
+	var header string
+	if h.timeFormat.Layout != "" {
+		header = h.timeFormat.Format(m.time)
+	}
+	if h.fileFlags != 0 {
+		file, line := m.Caller()
+		if file == "" {
+			file, line = "???", 0
+		}
+		if h.fileFlags&log.Lshortfile != 0 {
+			file = shortFile(file)
+		}
+		header += file + ":" + strconv.Itoa(line) + ": "
+	}
+	return h.stdLogger.Output(0, header+h.msgPrefix+string(formatted))
+}
+
+// WithFields implements Handler interface.
+func (h *StdHandler) WithFields(fields []Field) Handler {
+	c := *h
+	c.fields = mergeFields(h.fields, fields)
+	return &c
+}
+
+// WithName implements Handler interface.
+func (h *StdHandler) WithName(name string) Handler {
+	c := *h
+	c.name = name
+	return &c
+}
+
+// Writer returns the output destination of the handler.
+func (h *StdHandler) Writer() io.Writer {
+	return h.stdLogger.Writer()
+}
+
+/**
+
+LevelHandler
+
+*/
+
+// LevelHandler is the Handler that passes to the next handler only the messages
+// with the level allowed by its level filter.
+type LevelHandler struct {
+	filter LevelFilter
+	next   Handler
+}
+
+var _ Handler = &LevelHandler{}
+
+// NewLevelHandler creates new LevelHandler that passes the messages at 'level' or above to the 'next' handler.
+func NewLevelHandler(level Level, next Handler) *LevelHandler {
+	return &LevelHandler{filter: level, next: next}
+}
+
+// NewLevelFilterHandler creates new LevelHandler that passes the messages with the levels
+// allowed by the 'filter' to the 'next' handler, i.e. only DEBUG2..DEBUG levels using the LevelRange.
+func NewLevelFilterHandler(filter LevelFilter, next Handler) *LevelHandler {
+	return &LevelHandler{filter: filter, next: next}
+}
+
+// Enabled implements Handler interface.
+func (h *LevelHandler) Enabled(level Level) bool {
+	return h.filter.IsAllowed(level) && h.next.Enabled(level)
+}
+
+// Handle implements Handler interface.
+func (h *LevelHandler) Handle(m *Message) error {
+	return h.next.Handle(m)
+}
+
+// WithFields implements Handler interface.
+func (h *LevelHandler) WithFields(fields []Field) Handler {
+	return &LevelHandler{filter: h.filter, next: h.next.WithFields(fields)}
+}
+
+// WithName implements Handler interface.
+func (h *LevelHandler) WithName(name string) Handler {
+	return &LevelHandler{filter: h.filter, next: h.next.WithName(name)}
+}
+
+/**
+
+MultiHandler
+
+*/
+
+// MultiHandler is the Handler that passes the messages to multiple handlers.
+// Each handler gets its own copy of the message.
+type MultiHandler struct {
+	handlers []Handler
+}
+
+var _ Handler = &MultiHandler{}
+
+// NewMultiHandler creates new MultiHandler for given 'handlers'.
+func NewMultiHandler(handlers ...Handler) *MultiHandler {
+	return &MultiHandler{handlers: handlers}
+}
+
+// Enabled implements Handler interface. The level is enabled if any of the handlers enables it.
+func (h *MultiHandler) Enabled(level Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle implements Handler interface. It passes the message to all handlers that enables its level.
+// The first error returned by the handlers is returned.
+func (h *MultiHandler) Handle(m *Message) error {
+	var firstErr error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(m.level) {
+			continue
+		}
+		if err := handler.Handle(m.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WithFields implements Handler interface.
+func (h *MultiHandler) WithFields(fields []Field) Handler {
+	handlers := make([]Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithFields(fields)
+	}
+	return &MultiHandler{handlers: handlers}
+}
+
+// WithName implements Handler interface.
+func (h *MultiHandler) WithName(name string) Handler {
+	handlers := make([]Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithName(name)
+	}
+	return &MultiHandler{handlers: handlers}
+}
@@ -0,0 +1,155 @@
+package unilogger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler is the Handler that stores the handled messages.
+type recordingHandler struct {
+	messages *[]*Message
+	fields   []Field
+	name     string
+	err      error
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{messages: &[]*Message{}}
+}
+
+func (r *recordingHandler) Enabled(level Level) bool {
+	return true
+}
+
+func (r *recordingHandler) Handle(m *Message) error {
+	c := *m
+	c.fields = mergeFields(r.fields, m.fields)
+	c.logger = r.name
+	*r.messages = append(*r.messages, &c)
+	return r.err
+}
+
+func (r *recordingHandler) WithFields(fields []Field) Handler {
+	c := *r
+	c.fields = mergeFields(r.fields, fields)
+	return &c
+}
+
+func (r *recordingHandler) WithName(name string) Handler {
+	c := *r
+	c.name = name
+	return &c
+}
+
+// TestStdHandler tests the StdHandler used as the default BasicLogger handler.
+func TestStdHandler(t *testing.T) {
+	t.Run("ShortFile", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewBasicLogger(&buf, "prefix ", log.Lshortfile)
+
+		_, _, line, _ := runtime.Caller(0)
+		logger.Info("message")
+		assert.Equal(t, fmt.Sprintf("prefix handler_test.go:%d: INFO|%04x: message\n", line+1, logSequenceID), buf.String())
+	})
+
+	t.Run("MsgPrefix", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewBasicLogger(&buf, "prefix ", log.Lshortfile|log.Lmsgprefix)
+
+		_, _, line, _ := runtime.Caller(0)
+		logger.Info("message")
+		assert.Equal(t, fmt.Sprintf("handler_test.go:%d: prefix INFO|%04x: message\n", line+1, logSequenceID), buf.String())
+	})
+
+	t.Run("LongFile", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewBasicLogger(&buf, "", log.Llongfile)
+
+		_, file, line, _ := runtime.Caller(0)
+		logger.Info("message")
+		assert.Equal(t, fmt.Sprintf("%s:%d: INFO|%04x: message\n", file, line+1, logSequenceID), buf.String())
+	})
+
+	t.Run("Shared", func(t *testing.T) {
+		var buf bytes.Buffer
+		first := NewBasicLogger(&buf, "", 0, WithName("first")).With("a", 1)
+		second := NewBasicLogger(&buf, "", 0, WithName("second")).With("b", 2)
+
+		m := NewMessage(INFO, nil, "x").WithFields(Field{Key: "c", Value: 3})
+		first.LogMessage(m)
+		second.LogMessage(m)
+		assert.Equal(t, fmt.Sprintf("INFO|%04x|first: x a=1 c=3\nINFO|%04x|second: x b=2 c=3\n", m.ID(), m.ID()), buf.String())
+		assert.Equal(t, []Field{{Key: "c", Value: 3}}, m.Fields())
+		assert.Equal(t, "", m.Logger())
+	})
+
+	t.Run("Writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler := NewStdHandler(&buf, "", 0, nil)
+		assert.Equal(t, &buf, handler.Writer())
+	})
+}
+
+// TestWithHandler tests the BasicLogger with the custom handler.
+func TestWithHandler(t *testing.T) {
+	handler := newRecordingHandler()
+	logger := NewBasicLogger(nil, "", 0, WithHandler(handler), WithName("api"))
+	logger.SetLevel(DEBUG)
+
+	logger.With("id", 1).Debugf("message %d", 1)
+	require.Len(t, *handler.messages, 1)
+
+	msg := (*handler.messages)[0]
+	assert.Equal(t, DEBUG, msg.Level())
+	assert.Equal(t, "message 1", msg.Message())
+	assert.Equal(t, "api", msg.Logger())
+	assert.Equal(t, []Field{{Key: "id", Value: 1}}, msg.Fields())
+
+	file, _ := msg.Caller()
+	assert.Contains(t, file, "handler_test.go")
+
+	logger.Debug3("filtered")
+	assert.Len(t, *handler.messages, 1)
+}
+
+// TestLevelHandler tests the LevelHandler.
+func TestLevelHandler(t *testing.T) {
+	next := newRecordingHandler()
+	handler := NewLevelHandler(WARNING, next)
+	assert.False(t, handler.Enabled(INFO))
+	assert.True(t, handler.Enabled(ERROR))
+
+	logger := NewBasicLogger(nil, "", 0, WithHandler(handler))
+	logger.Info("filtered")
+	logger.With("a", 1).Error("passed")
+
+	require.Len(t, *next.messages, 1)
+	assert.Equal(t, "passed", (*next.messages)[0].Message())
+	assert.Equal(t, []Field{{Key: "a", Value: 1}}, (*next.messages)[0].Fields())
+}
+
+// TestMultiHandler tests the MultiHandler.
+func TestMultiHandler(t *testing.T) {
+	first := newRecordingHandler()
+	first.err = errors.New("first error")
+	second := newRecordingHandler()
+
+	handler := NewMultiHandler(NewLevelHandler(ERROR, first), second)
+	assert.True(t, handler.Enabled(INFO))
+
+	named := handler.WithName("multi").WithFields([]Field{{Key: "a", Value: 1}})
+	assert.NoError(t, named.Handle(NewMessage(INFO, nil, "info")))
+	assert.EqualError(t, named.Handle(NewMessage(ERROR, nil, "error")), "first error")
+
+	assert.Len(t, *first.messages, 1)
+	require.Len(t, *second.messages, 2)
+	assert.Equal(t, "multi", (*second.messages)[0].Logger())
+	assert.Equal(t, []Field{{Key: "a", Value: 1}}, (*second.messages)[1].Fields())
+}
@@ -0,0 +1,143 @@
+package unilogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// LevelsHandler is the http.Handler that exposes the loggers registered in the Registry
+// and allows to change their levels. It supports the following methods:
+//	# GET - lists the registered loggers with their current and default levels as JSON.
+//	  The optional 'name' query parameter filters the loggers by the name pattern.
+//	# PUT, POST - sets the level of the loggers matching the 'name' pattern. The parameters
+//	  'name', 'level' and optional 'ttl' are read from the JSON body or the form values.
+//	  The 'level' is parsed using ParseLevel and the 'ttl' using time.ParseDuration.
+//	  If the 'ttl' is provided the previous levels are restored after its duration.
+//	  The response contains the changed loggers.
+type LevelsHandler struct {
+	registry *Registry
+}
+
+var _ http.Handler = &LevelsHandler{}
+
+// NewLevelsHandler creates new LevelsHandler for the 'registry'.
+// If the 'registry' is nil the default registry is used.
+func NewLevelsHandler(registry *Registry) *LevelsHandler {
+	if registry == nil {
+		registry = defaultRegistry
+	}
+	return &LevelsHandler{registry: registry}
+}
+
+// levelsRequest is the body of the level change request.
+type levelsRequest struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+	TTL   string `json:"ttl"`
+}
+
+// loggerInfoResponse is the JSON representation of the LoggerInfo.
+type loggerInfoResponse struct {
+	Name         string `json:"name"`
+	Level        string `json:"level"`
+	DefaultLevel string `json:"default_level"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP implements http.Handler interface.
+func (h *LevelsHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		pattern := req.URL.Query().Get("name")
+		if pattern == "" {
+			pattern = "*"
+		}
+		infos, err := h.loggers(pattern)
+		if err != nil {
+			h.writeError(rw, http.StatusBadRequest, err)
+			return
+		}
+		h.writeJSON(rw, http.StatusOK, infos)
+	case http.MethodPut, http.MethodPost:
+		h.setLevel(rw, req)
+	default:
+		rw.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		h.writeError(rw, http.StatusMethodNotAllowed, fmt.Errorf("method: '%s' is not allowed", req.Method))
+	}
+}
+
+func (h *LevelsHandler) setLevel(rw http.ResponseWriter, req *http.Request) {
+	var body levelsRequest
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			h.writeError(rw, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+			return
+		}
+	} else {
+		body.Name = req.FormValue("name")
+		body.Level = req.FormValue("level")
+		body.TTL = req.FormValue("ttl")
+	}
+
+	if body.Name == "" {
+		h.writeError(rw, http.StatusBadRequest, fmt.Errorf("no logger name provided"))
+		return
+	}
+	level, err := parseLevelName(body.Level)
+	if err != nil {
+		h.writeError(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	if body.TTL != "" {
+		var ttl time.Duration
+		ttl, err = time.ParseDuration(body.TTL)
+		if err != nil || ttl <= 0 {
+			h.writeError(rw, http.StatusBadRequest, fmt.Errorf("invalid ttl: '%s'", body.TTL))
+			return
+		}
+		_, err = h.registry.SetLevelFor(body.Name, level, ttl)
+	} else {
+		_, err = h.registry.SetLevel(body.Name, level)
+	}
+	if err != nil {
+		h.writeError(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	infos, _ := h.loggers(body.Name)
+	if len(infos) == 0 {
+		h.writeError(rw, http.StatusNotFound, fmt.Errorf("no loggers matching: '%s'", body.Name))
+		return
+	}
+	h.writeJSON(rw, http.StatusOK, infos)
+}
+
+func (h *LevelsHandler) loggers(pattern string) ([]loggerInfoResponse, error) {
+	infos, err := h.registry.LoggersMatching(pattern)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]loggerInfoResponse, len(infos))
+	for i, info := range infos {
+		response[i] = loggerInfoResponse{Name: info.Name, Level: info.Level.String(), DefaultLevel: info.DefaultLevel.String()}
+	}
+	return response, nil
+}
+
+func (h *LevelsHandler) writeError(rw http.ResponseWriter, status int, err error) {
+	h.writeJSON(rw, status, errorResponse{Error: err.Error()})
+}
+
+func (h *LevelsHandler) writeJSON(rw http.ResponseWriter, status int, value interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(value)
+}
@@ -0,0 +1,124 @@
+package unilogger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLevelsHandler tests the LevelsHandler.
+func TestLevelsHandler(t *testing.T) {
+	root := NewBasicLogger(ioutil.Discard, "", 0)
+	storage := root.Named("storage")
+	httpLogger := root.Named("http")
+
+	registry := NewRegistry()
+	registry.MustRegister("storage", storage)
+	registry.MustRegister("http", httpLogger)
+
+	handler := NewLevelsHandler(registry)
+
+	serve := func(req *http.Request) (*httptest.ResponseRecorder, []loggerInfoResponse) {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		var infos []loggerInfoResponse
+		if rw.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &infos))
+		}
+		return rw, infos
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		rw, infos := serve(httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+		assert.Equal(t, []loggerInfoResponse{
+			{Name: "http", Level: "INFO", DefaultLevel: "INFO"},
+			{Name: "storage", Level: "INFO", DefaultLevel: "INFO"},
+		}, infos)
+
+		_, infos = serve(httptest.NewRequest(http.MethodGet, "/?name=stor*", nil))
+		assert.Len(t, infos, 1)
+	})
+
+	t.Run("PutJSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"name":"storage","level":"debug2"}`))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		rw, infos := serve(req)
+		require.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, []loggerInfoResponse{{Name: "storage", Level: "DEBUG2", DefaultLevel: "INFO"}}, infos)
+		assert.Equal(t, DEBUG2, storage.GetLevel())
+	})
+
+	t.Run("PostFormTTL", func(t *testing.T) {
+		form := url.Values{"name": {"http"}, "level": {"error"}, "ttl": {"20ms"}}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		rw, _ := serve(req)
+		require.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, ERROR, httpLogger.GetLevel())
+
+		waitFor(t, func() bool {
+			return httpLogger.GetLevel() == INFO && httpLogger.IsLevelInherited()
+		})
+	})
+
+	t.Run("PutTTLCancelled", func(t *testing.T) {
+		put := func(body string) {
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rw, _ := serve(req)
+			require.Equal(t, http.StatusOK, rw.Code)
+		}
+
+		put(`{"name":"http","level":"debug2","ttl":"1h"}`)
+		assert.Equal(t, DEBUG2, httpLogger.GetLevel())
+
+		put(`{"name":"http","level":"warning"}`)
+		assert.Equal(t, WARNING, httpLogger.GetLevel())
+		assert.False(t, httpLogger.AtomicLevel().IsElevated())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		requests := map[string]int{
+			`{"name":"storage","level":"unknown"}`:            http.StatusBadRequest,
+			`{"level":"debug"}`:                               http.StatusBadRequest,
+			`{"name":"storage","level":"debug","ttl":"long"}`: http.StatusBadRequest,
+			`{"name":"[","level":"debug"}`:                    http.StatusBadRequest,
+			`{"name":"none","level":"debug"}`:                 http.StatusNotFound,
+			`{`:                                               http.StatusBadRequest,
+		}
+		for body, status := range requests {
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rw, _ := serve(req)
+			assert.Equal(t, status, rw.Code, body)
+		}
+
+		rw, _ := serve(httptest.NewRequest(http.MethodDelete, "/", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+		assert.NotEmpty(t, rw.Header().Get("Allow"))
+	})
+}
+
+// waitFor waits until the 'condition' is satisfied or fails the test after a second.
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not satisfied in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
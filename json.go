@@ -0,0 +1,149 @@
+package unilogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// WithJSONOutput is the BasicLogger option that sets the logger output to the JSON lines.
+// It is a shortcut for the WithFormatter(&JSONFormatter{}) option.
+func WithJSONOutput() Option {
+	return WithFormatter(&JSONFormatter{})
+}
+
+// JSONFormatter is the formatter that writes the message as a single line JSON object
+// containing the following keys:
+//	# time - message time formatted using the 'Time' format, if set
+//	# level - message level name
+//	# seq - message sequence id
+//	# logger - name of the logger, if set
+//	# msg - message text
+//	# caller - file and line where the message was logged
+// followed by the message fields. The fields with the keys clashing with the above keys
+// are prefixed with the 'fields.', i.e. 'fields.msg' and the repeated keys are suffixed
+// with the number of their repetition, i.e. '!BADKEY.1', so that the object keys are unique.
+type JSONFormatter struct {
+	// Time defines the format of the message time.
+	Time TimeFormat
+}
+
+var _ Formatter = &JSONFormatter{}
+
+// Format implements Formatter interface.
+func (j *JSONFormatter) Format(m *Message) ([]byte, error) {
+	buf := make([]byte, 0, 128)
+	buf = append(buf, '{')
+	if !m.time.IsZero() {
+		buf = append(buf, `"time":`...)
+		buf = appendJSONString(buf, j.Time.Format(m.time))
+		buf = append(buf, ',')
+	}
+	buf = append(buf, `"level":`...)
+	buf = appendJSONString(buf, m.level.String())
+	buf = append(buf, `,"seq":`...)
+	buf = strconv.AppendUint(buf, m.id, 10)
+	if m.logger != "" {
+		buf = append(buf, `,"logger":`...)
+		buf = appendJSONString(buf, m.logger)
+	}
+	buf = append(buf, `,"msg":`...)
+	buf = appendJSONString(buf, m.getMessage())
+	if caller := m.shortCaller(); caller != "" {
+		buf = append(buf, `,"caller":`...)
+		buf = appendJSONString(buf, caller)
+	}
+	keys := fieldKeys(m.fields)
+	for i, field := range m.fields {
+		buf = append(buf, ',')
+		buf = appendJSONString(buf, keys[i])
+		buf = append(buf, ':')
+		buf = appendJSONValue(buf, field.Value)
+	}
+	buf = append(buf, '}')
+	return buf, nil
+}
+
+// appendJSONValue appends the JSON representation of the 'value' to the buffer.
+// Values that could not be marshaled are written as their string representation.
+func appendJSONValue(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case string:
+		return appendJSONString(buf, v)
+	case bool:
+		return strconv.AppendBool(buf, v)
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(buf, v, 10)
+	case int32:
+		return strconv.AppendInt(buf, int64(v), 10)
+	case uint:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(buf, v, 10)
+	case uint32:
+		return strconv.AppendUint(buf, uint64(v), 10)
+	case time.Time:
+		return appendJSONString(buf, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return appendJSONString(buf, v.String())
+	case error:
+		return appendJSONString(buf, v.Error())
+	case json.Marshaler:
+		// marshaled below.
+	case fmt.Stringer:
+		return appendJSONString(buf, v.String())
+	}
+	marshaled, err := json.Marshal(value)
+	if err != nil {
+		return appendJSONString(buf, fmt.Sprintf("%+v", value))
+	}
+	return append(buf, marshaled...)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends the quoted and escaped JSON string to the buffer.
+// Newlines, quotes, backslashes and control characters are escaped, invalid UTF-8
+// sequences are replaced with the unicode replacement character.
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf = append(buf, '\\', c)
+			case c == '\n':
+				buf = append(buf, '\\', 'n')
+			case c == '\r':
+				buf = append(buf, '\\', 'r')
+			case c == '\t':
+				buf = append(buf, '\\', 't')
+			case c < 0x20 || c == 0x7f:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			default:
+				buf = append(buf, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, `\ufffd`...)
+		case r == '\u2028' || r == '\u2029':
+			buf = append(buf, `\u202`...)
+			buf = append(buf, hexDigits[r&0xf])
+		default:
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buf, '"')
+}
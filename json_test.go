@@ -0,0 +1,109 @@
+package unilogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJSONOutput tests the BasicLogger with the JSON output.
+func TestJSONOutput(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewBasicLogger(&buf, "prefix ", 0, WithJSONOutput())
+
+	t.Run("Message", func(t *testing.T) {
+		buf.Reset()
+		logger.With("user", "john", "count", 3, "err", errors.New("failed")).Infof("multi\nline \"%s\"\x01", "quoted")
+
+		line := buf.String()
+		require.True(t, strings.HasSuffix(line, "}\n"))
+		assert.Equal(t, 1, strings.Count(line, "\n"))
+
+		record := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+
+		assert.Equal(t, "INFO", record["level"])
+		assert.Equal(t, float64(logSequenceID), record["seq"])
+		assert.Equal(t, "multi\nline \"quoted\"\x01", record["msg"])
+		assert.Equal(t, "john", record["user"])
+		assert.Equal(t, float64(3), record["count"])
+		assert.Equal(t, "failed", record["err"])
+		assert.Contains(t, record["caller"], "/json_test.go:")
+		assert.NotEmpty(t, record["time"])
+	})
+
+	t.Run("ReservedKeys", func(t *testing.T) {
+		buf.Reset()
+		logger.With("msg", "x", "level", "y", "logger", "z").Info("hi")
+
+		line := buf.String()
+		assert.Equal(t, 1, strings.Count(line, `"msg":`))
+		assert.Equal(t, 1, strings.Count(line, `"level":`))
+
+		record := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		assert.Equal(t, "hi", record["msg"])
+		assert.Equal(t, "INFO", record["level"])
+		assert.Nil(t, record["logger"])
+		assert.Equal(t, "x", record["fields.msg"])
+		assert.Equal(t, "y", record["fields.level"])
+		assert.Equal(t, "z", record["fields.logger"])
+	})
+
+	t.Run("DuplicateKeys", func(t *testing.T) {
+		buf.Reset()
+		logger.With(1, 2, "msg", "x", "fields.msg", "y").Info("hi")
+
+		line := buf.String()
+		assert.Equal(t, 1, strings.Count(line, `"!BADKEY":`))
+		assert.Equal(t, 1, strings.Count(line, `"fields.msg":`))
+
+		record := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		assert.Equal(t, float64(1), record["!BADKEY"])
+		assert.Equal(t, float64(2), record["!BADKEY.1"])
+		assert.Equal(t, "x", record["fields.msg"])
+		assert.Equal(t, "y", record["fields.msg.1"])
+	})
+
+	t.Run("SubLogger", func(t *testing.T) {
+		buf.Reset()
+		sub := logger.SubLogger()
+		// the sublogger caller is the caller of the function calling its methods.
+		warning := func(msg string) { sub.Warning(msg) }
+		warning("sub")
+
+		record := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "WARNING", record["level"])
+		assert.Equal(t, "sub", record["msg"])
+		assert.Contains(t, record["caller"], "/json_test.go:")
+
+		buf.Reset()
+		sub.Warning("direct")
+		record = map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.NotContains(t, record["caller"], "/json_test.go:")
+	})
+}
+
+// TestAppendJSONString tests the appendJSONString function.
+func TestAppendJSONString(t *testing.T) {
+	values := []string{"plain", "new\nline", "tab\t", "quote\"backslash\\", "\x00\x1f\x7f", "\u2028\u2029", "zażółć", "\xff"}
+	for _, value := range values {
+		encoded := appendJSONString(nil, value)
+
+		var decoded string
+		require.NoError(t, json.Unmarshal(encoded, &decoded), string(encoded))
+		if value == "\xff" {
+			assert.Equal(t, "\ufffd", decoded)
+			continue
+		}
+		assert.Equal(t, value, decoded)
+	}
+}
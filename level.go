@@ -0,0 +1,425 @@
+package unilogger
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// levelAliases are the case insensitive names parsed into the levels.
+var levelAliases = map[string]Level{
+	"TRACE":     TRACE,
+	"DEBUG3":    DEBUG3,
+	"DEBUG2":    DEBUG2,
+	"DEBUG":     DEBUG,
+	"INFO":      INFO,
+	"NOTICE":    NOTICE,
+	"WARNING":   WARNING,
+	"WARN":      WARNING,
+	"ERROR":     ERROR,
+	"ERR":       ERROR,
+	"CRITICAL":  CRITICAL,
+	"CRIT":      CRITICAL,
+	"FATAL":     CRITICAL,
+	"PANIC":     CRITICAL,
+	"ALERT":     ALERT,
+	"EMERGENCY": EMERGENCY,
+	"EMERG":     EMERGENCY,
+	"PRINT":     PRINT,
+}
+
+// parseLevelName parses the level name, alias or numeric value.
+// Returns error if the level is not recognized.
+func parseLevelName(name string) (Level, error) {
+	name = strings.TrimSpace(name)
+	upper := strings.ToUpper(name)
+	if level, ok := levelAliases[upper]; ok {
+		return level, nil
+	}
+	table := loadLevelTable()
+	if level, ok := table.levels[upper]; ok {
+		return level, nil
+	}
+	if number, err := strconv.Atoi(name); err == nil {
+		level := Level(number)
+		if _, ok := levelNames[level]; ok {
+			return level, nil
+		}
+		if _, ok := table.names[level]; ok {
+			return level, nil
+		}
+	}
+	return UNKNOWN, fmt.Errorf("unknown level: '%s'", name)
+}
+
+// levelName gets the name of the level that could be parsed back into the same level.
+func (l Level) levelName() (string, error) {
+	if l == PRINT {
+		return "PRINT", nil
+	}
+	if name, ok := levelNames[l]; ok {
+		return name, nil
+	}
+	if name, ok := loadLevelTable().names[l]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown level: %d", int(l))
+}
+
+var (
+	_ encoding.TextMarshaler   = DEBUG
+	_ encoding.TextUnmarshaler = (*Level)(nil)
+	_ json.Marshaler           = DEBUG
+	_ json.Unmarshaler         = (*Level)(nil)
+	_ flag.Value               = (*Level)(nil)
+)
+
+// MarshalText implements encoding.TextMarshaler interface.
+// Contrary to the String method the PRINT level is marshaled as 'PRINT'.
+func (l Level) MarshalText() ([]byte, error) {
+	name, err := l.levelName()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+// The text is parsed in the same manner as in ParseLevel function.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := parseLevelName(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler interface. The level is marshaled as a JSON string.
+func (l Level) MarshalJSON() ([]byte, error) {
+	name, err := l.levelName()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(name)
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// The level might be a JSON string with the level name or a JSON number.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		return l.UnmarshalText([]byte(name))
+	}
+	return l.UnmarshalText(data)
+}
+
+// Set implements flag.Value interface.
+func (l *Level) Set(value string) error {
+	return l.UnmarshalText([]byte(value))
+}
+
+/**
+
+Severity
+
+*/
+
+// levelSeverities are the severities of the built-in levels indexed by the level.
+// The values are compatible with the slog.Level values, i.e. DEBUG is -4 and ERROR is 8.
+// The PRINT level is higher than any other level, so that it is always logged.
+var levelSeverities = [...]int{
+	DEBUG3:    -12,
+	DEBUG2:    -8,
+	DEBUG:     -4,
+	INFO:      0,
+	WARNING:   4,
+	ERROR:     8,
+	CRITICAL:  12,
+	PRINT:     math.MaxInt32 - 1,
+	UNKNOWN:   math.MaxInt32,
+	TRACE:     -16,
+	NOTICE:    2,
+	ALERT:     16,
+	EMERGENCY: 20,
+}
+
+// Severity gets the numeric severity of the level used to compare the levels.
+// The severities of the built-in levels are compatible with the slog.Level values:
+//	# TRACE     -16
+//	# DEBUG3    -12
+//	# DEBUG2     -8
+//	# DEBUG      -4
+//	# INFO        0
+//	# NOTICE      2
+//	# WARNING     4
+//	# ERROR       8
+//	# CRITICAL   12
+//	# ALERT      16
+//	# EMERGENCY  20
+// The PRINT level is higher than all the other levels and the unknown levels have the highest severity.
+func (l Level) Severity() int {
+	if l >= 0 && int(l) < len(levelSeverities) {
+		return levelSeverities[l]
+	}
+	if severity, ok := loadLevelTable().severities[l]; ok {
+		return severity
+	}
+	return levelSeverities[UNKNOWN]
+}
+
+// Levels gets all the known levels, including the registered ones, sorted by their severity.
+// The PRINT and UNKNOWN levels are not included.
+func Levels() []Level {
+	ordered := loadLevelTable().ordered
+	levels := make([]Level, len(ordered))
+	copy(levels, ordered)
+	return levels
+}
+
+// levelForSeverity gets the level with the highest severity lower or equal to the 'severity'.
+// If all the levels have higher severity the lowest level is returned.
+func levelForSeverity(severity int) Level {
+	ordered := loadLevelTable().ordered
+	i := sort.Search(len(ordered), func(i int) bool {
+		return ordered[i].Severity() > severity
+	})
+	if i == 0 {
+		return ordered[0]
+	}
+	return ordered[i-1]
+}
+
+/**
+
+Custom levels
+
+*/
+
+// levelTable contains the registered custom levels. It is replaced on each registration,
+// so that the readers don't need any locking.
+type levelTable struct {
+	names      map[Level]string
+	levels     map[string]Level
+	severities map[Level]int
+	ordered    []Level
+}
+
+var (
+	levelTableValue = newLevelTableValue()
+	levelTableLock  sync.Mutex
+	lastLevel       = EMERGENCY
+)
+
+func newLevelTableValue() *atomic.Value {
+	v := &atomic.Value{}
+	v.Store(newLevelTable(nil))
+	return v
+}
+
+func loadLevelTable() *levelTable {
+	return levelTableValue.Load().(*levelTable)
+}
+
+// newLevelTable creates new level table copying the custom levels from the 'prev' table.
+func newLevelTable(prev *levelTable) *levelTable {
+	t := &levelTable{
+		names:      map[Level]string{},
+		levels:     map[string]Level{},
+		severities: map[Level]int{},
+	}
+	if prev == nil {
+		for level := range levelNames {
+			if level != PRINT {
+				t.ordered = append(t.ordered, level)
+			}
+		}
+		t.sort()
+		return t
+	}
+	for level, name := range prev.names {
+		t.names[level] = name
+		t.levels[name] = level
+		t.severities[level] = prev.severities[level]
+	}
+	t.ordered = append(t.ordered, prev.ordered...)
+	return t
+}
+
+func (t *levelTable) sort() {
+	sort.SliceStable(t.ordered, func(i, j int) bool {
+		return t.severity(t.ordered[i]) < t.severity(t.ordered[j])
+	})
+}
+
+func (t *levelTable) severity(level Level) int {
+	if level >= 0 && int(level) < len(levelSeverities) {
+		return levelSeverities[level]
+	}
+	return t.severities[level]
+}
+
+// RegisterLevel registers new level with the 'name' and numeric 'severity' (see Level.Severity).
+// The registered level is compared with the other levels by the severity and could be parsed
+// using its case insensitive name. The name must consist of letters, digits, '_' or '-'
+// and must not start with a digit. Returns error if the name is not valid or is already used,
+// or if the severity is not lower than the PRINT level severity.
+func RegisterLevel(name string, severity int) (Level, error) {
+	if !isValidLevelName(name) {
+		return UNKNOWN, fmt.Errorf("invalid level name: '%s'", name)
+	}
+	if severity >= PRINT.Severity() {
+		return UNKNOWN, fmt.Errorf("level: '%s' severity: %d is out of range", name, severity)
+	}
+	name = strings.ToUpper(name)
+
+	levelTableLock.Lock()
+	defer levelTableLock.Unlock()
+
+	prev := loadLevelTable()
+	if _, ok := levelAliases[name]; ok {
+		return UNKNOWN, fmt.Errorf("level: '%s' is already registered", name)
+	}
+	if _, ok := prev.levels[name]; ok {
+		return UNKNOWN, fmt.Errorf("level: '%s' is already registered", name)
+	}
+	lastLevel++
+	level := lastLevel
+
+	t := newLevelTable(prev)
+	t.names[level] = name
+	t.levels[name] = level
+	t.severities[level] = severity
+	t.ordered = append(t.ordered, level)
+	t.sort()
+	levelTableValue.Store(t)
+	return level, nil
+}
+
+// MustRegisterLevel registers new level with the 'name' and 'severity'. Panics on error.
+func MustRegisterLevel(name string, severity int) Level {
+	level, err := RegisterLevel(name, severity)
+	if err != nil {
+		panic(err)
+	}
+	return level
+}
+
+func isValidLevelName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+/**
+
+Syslog
+
+*/
+
+// Syslog severities as defined in RFC 5424.
+const (
+	SyslogEmergency = iota
+	SyslogAlert
+	SyslogCritical
+	SyslogError
+	SyslogWarning
+	SyslogNotice
+	SyslogInfo
+	SyslogDebug
+)
+
+// LevelToSyslog maps the level into the syslog severity. The levels between
+// the syslog severities are mapped to the lower severity i.e.: DEBUG2 is SyslogDebug.
+// The PRINT level is mapped to SyslogInfo.
+func LevelToSyslog(level Level) int {
+	if level == PRINT {
+		return SyslogInfo
+	}
+	switch severity := level.Severity(); {
+	case severity < INFO.Severity():
+		return SyslogDebug
+	case severity < NOTICE.Severity():
+		return SyslogInfo
+	case severity < WARNING.Severity():
+		return SyslogNotice
+	case severity < ERROR.Severity():
+		return SyslogWarning
+	case severity < CRITICAL.Severity():
+		return SyslogError
+	case severity < ALERT.Severity():
+		return SyslogCritical
+	case severity < EMERGENCY.Severity():
+		return SyslogAlert
+	default:
+		return SyslogEmergency
+	}
+}
+
+// LevelFromSyslog maps the syslog 'severity' into the level.
+// The severities higher than SyslogDebug are mapped to DEBUG and lower than SyslogEmergency to EMERGENCY.
+func LevelFromSyslog(severity int) Level {
+	switch {
+	case severity <= SyslogEmergency:
+		return EMERGENCY
+	case severity == SyslogAlert:
+		return ALERT
+	case severity == SyslogCritical:
+		return CRITICAL
+	case severity == SyslogError:
+		return ERROR
+	case severity == SyslogWarning:
+		return WARNING
+	case severity == SyslogNotice:
+		return NOTICE
+	case severity == SyslogInfo:
+		return INFO
+	default:
+		return DEBUG
+	}
+}
+
+// verbosityLevels are the levels stepped through by the signals, sorted from the least verbose one.
+var verbosityLevels = []Level{INFO, DEBUG, DEBUG2, DEBUG3}
+
+// moreVerboseLevel gets the first of the verbosityLevels with the severity lower than the 'level' severity.
+// If there is no such level, i.e. for the DEBUG3 level, the 'level' is returned.
+func moreVerboseLevel(level Level) Level {
+	for _, l := range verbosityLevels {
+		if l.Severity() < level.Severity() {
+			return l
+		}
+	}
+	return level
+}
+
+// lessVerboseLevel gets the last of the verbosityLevels with the severity higher than the 'level' severity.
+// If there is no such level, i.e. for the INFO level, the 'level' is returned.
+func lessVerboseLevel(level Level) Level {
+	for i := len(verbosityLevels) - 1; i >= 0; i-- {
+		if verbosityLevels[i].Severity() > level.Severity() {
+			return verbosityLevels[i]
+		}
+	}
+	return level
+}
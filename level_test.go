@@ -0,0 +1,276 @@
+package unilogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseLevel tests the level parsing with aliases and numeric values.
+func TestParseLevel(t *testing.T) {
+	cases := map[string]Level{
+		"debug3":    DEBUG3,
+		"trace":     TRACE,
+		"DEBUG2":    DEBUG2,
+		" Debug ":   DEBUG,
+		"info":      INFO,
+		"warn":      WARNING,
+		"Warning":   WARNING,
+		"err":       ERROR,
+		"error":     ERROR,
+		"crit":      CRITICAL,
+		"fatal":     CRITICAL,
+		"panic":     CRITICAL,
+		"print":     PRINT,
+		"0":         DEBUG3,
+		"2":         DEBUG,
+		"3":         INFO,
+		"4":         WARNING,
+		"unknown":   UNKNOWN,
+		"8":         UNKNOWN,
+		"-1":        UNKNOWN,
+		"":          UNKNOWN,
+		"something": UNKNOWN,
+	}
+	for name, expected := range cases {
+		assert.Equal(t, expected, ParseLevel(name), name)
+	}
+}
+
+// TestLevelText tests the text marshaling and unmarshaling of the levels.
+func TestLevelText(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, level := range append(Levels(), PRINT) {
+			text, err := level.MarshalText()
+			require.NoError(t, err)
+
+			var parsed Level
+			require.NoError(t, parsed.UnmarshalText(text))
+			assert.Equal(t, level, parsed)
+		}
+	})
+
+	t.Run("Print", func(t *testing.T) {
+		text, err := PRINT.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, "PRINT", string(text))
+		assert.Equal(t, "INFO", PRINT.String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := UNKNOWN.MarshalText()
+		assert.Error(t, err)
+
+		_, err = Level(100).MarshalText()
+		assert.Error(t, err)
+
+		level := WARNING
+		err = level.UnmarshalText([]byte("verbose"))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "unknown level: 'verbose'")
+		}
+		assert.Equal(t, WARNING, level)
+	})
+}
+
+// TestLevelJSON tests the JSON marshaling and unmarshaling of the levels.
+func TestLevelJSON(t *testing.T) {
+	type config struct {
+		Level  Level   `json:"level"`
+		Levels []Level `json:"levels"`
+	}
+
+	t.Run("Marshal", func(t *testing.T) {
+		data, err := json.Marshal(config{Level: WARNING, Levels: []Level{DEBUG2, PRINT}})
+		require.NoError(t, err)
+		assert.Equal(t, `{"level":"WARNING","levels":["DEBUG2","PRINT"]}`, string(data))
+
+		_, err = json.Marshal(config{Level: UNKNOWN})
+		assert.Error(t, err)
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		var c config
+		require.NoError(t, json.Unmarshal([]byte(`{"level":"warn","levels":[1, "trace", "PRINT"]}`), &c))
+		assert.Equal(t, WARNING, c.Level)
+		assert.Equal(t, []Level{DEBUG2, TRACE, PRINT}, c.Levels)
+
+		assert.Error(t, json.Unmarshal([]byte(`{"level":"verbose"}`), &c))
+		assert.Error(t, json.Unmarshal([]byte(`{"level":42}`), &c))
+		assert.Error(t, json.Unmarshal([]byte(`{"level":true}`), &c))
+	})
+}
+
+// TestLevelFlag tests the Level used as the flag.Value.
+func TestLevelFlag(t *testing.T) {
+	level := INFO
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&level, "level", "logging level")
+
+	require.NoError(t, fs.Parse([]string{"-level", "err"}))
+	assert.Equal(t, ERROR, level)
+
+	fs.SetOutput(nopWriter{})
+	assert.Error(t, fs.Parse([]string{"-level", "verbose"}))
+	assert.Equal(t, ERROR, level)
+}
+
+// TestLevelSeverity tests the levels comparison by their severity.
+func TestLevelSeverity(t *testing.T) {
+	assert.Equal(t, []Level{TRACE, DEBUG3, DEBUG2, DEBUG, INFO, NOTICE, WARNING, ERROR, CRITICAL, ALERT, EMERGENCY}, Levels()[:11])
+
+	assert.True(t, DEBUG3.IsAllowed(DEBUG3))
+	assert.False(t, DEBUG3.IsAllowed(TRACE))
+	assert.True(t, INFO.IsAllowed(NOTICE))
+	assert.False(t, WARNING.IsAllowed(NOTICE))
+	assert.True(t, CRITICAL.IsAllowed(EMERGENCY))
+	assert.True(t, EMERGENCY.IsAllowed(PRINT))
+	assert.False(t, PRINT.IsAllowed(EMERGENCY))
+	assert.False(t, UNKNOWN.IsAllowed(PRINT))
+	assert.Equal(t, UNKNOWN.Severity(), Level(1000).Severity())
+}
+
+// TestRegisterLevel tests the custom levels registration.
+func TestRegisterLevel(t *testing.T) {
+	restoreLevelTable(t)
+
+	audit, err := RegisterLevel("audit", 6)
+	require.NoError(t, err)
+
+	t.Run("Name", func(t *testing.T) {
+		assert.Equal(t, "AUDIT", audit.String())
+		assert.Equal(t, audit, ParseLevel("Audit"))
+
+		text, err := audit.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, "AUDIT", string(text))
+
+		data, err := json.Marshal(audit)
+		require.NoError(t, err)
+
+		var level Level
+		require.NoError(t, json.Unmarshal(data, &level))
+		assert.Equal(t, audit, level)
+	})
+
+	t.Run("Severity", func(t *testing.T) {
+		assert.Equal(t, 6, audit.Severity())
+		assert.True(t, WARNING.IsAllowed(audit))
+		assert.False(t, audit.IsAllowed(WARNING))
+		assert.True(t, audit.IsAllowed(ERROR))
+
+		levels := Levels()
+		for i, level := range levels {
+			if level == audit {
+				assert.Equal(t, WARNING, levels[i-1])
+				assert.Equal(t, ERROR, levels[i+1])
+			}
+		}
+		assert.Contains(t, levels, audit)
+		assert.Equal(t, SyslogWarning, LevelToSyslog(audit))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := RegisterLevel("audit", 7)
+		assert.Error(t, err)
+
+		_, err = RegisterLevel("Warn", 7)
+		assert.Error(t, err)
+
+		_, err = RegisterLevel("", 7)
+		assert.Error(t, err)
+
+		_, err = RegisterLevel("1st", 7)
+		assert.Error(t, err)
+
+		_, err = RegisterLevel("my level", 7)
+		assert.Error(t, err)
+
+		_, err = RegisterLevel("loud", PRINT.Severity())
+		assert.Error(t, err)
+
+		assert.Panics(t, func() { MustRegisterLevel("audit", 7) })
+	})
+
+	t.Run("BasicLogger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewBasicLogger(&buf, "", 0)
+		logger.SetLevel(audit)
+
+		logger.LogMessage(NewMessage(WARNING, nil, "skipped"))
+		assert.Empty(t, buf.String())
+
+		logger.LogMessage(NewMessage(audit, nil, "logged"))
+		assert.Contains(t, buf.String(), "AUDIT|")
+		assert.Contains(t, buf.String(), ": logged")
+	})
+
+	t.Run("StdLogger", func(t *testing.T) {
+		var buf bytes.Buffer
+		wrapper := MustGetLoggerWrapper(log.New(&buf, "", 0))
+
+		wrapper.LogMessage(NewMessage(audit, nil, "logged"))
+		assert.Equal(t, "AUDIT: logged\n", buf.String())
+	})
+}
+
+// TestSyslogLevels tests the syslog severities mapping.
+func TestSyslogLevels(t *testing.T) {
+	levels := []Level{EMERGENCY, ALERT, CRITICAL, ERROR, WARNING, NOTICE, INFO, DEBUG}
+	for severity, level := range levels {
+		assert.Equal(t, level, LevelFromSyslog(severity))
+		assert.Equal(t, severity, LevelToSyslog(level))
+	}
+	assert.Equal(t, SyslogDebug, LevelToSyslog(TRACE))
+	assert.Equal(t, SyslogDebug, LevelToSyslog(DEBUG3))
+	assert.Equal(t, SyslogInfo, LevelToSyslog(PRINT))
+	assert.Equal(t, DEBUG, LevelFromSyslog(10))
+	assert.Equal(t, EMERGENCY, LevelFromSyslog(-1))
+}
+
+// TestLevelSteps tests stepping through the verbosity levels.
+func TestLevelSteps(t *testing.T) {
+	assert.Equal(t, DEBUG, moreVerboseLevel(INFO))
+	assert.Equal(t, DEBUG3, moreVerboseLevel(DEBUG2))
+	assert.Equal(t, DEBUG3, moreVerboseLevel(DEBUG3))
+	assert.Equal(t, TRACE, moreVerboseLevel(TRACE))
+	assert.Equal(t, INFO, moreVerboseLevel(WARNING))
+
+	assert.Equal(t, INFO, lessVerboseLevel(DEBUG))
+	assert.Equal(t, DEBUG3, lessVerboseLevel(TRACE))
+	assert.Equal(t, INFO, lessVerboseLevel(INFO))
+	assert.Equal(t, WARNING, lessVerboseLevel(WARNING))
+	assert.Equal(t, NOTICE, lessVerboseLevel(NOTICE))
+
+	restoreLevelTable(t)
+	verbose := MustRegisterLevel("verbose", DEBUG.Severity()-2)
+	assert.Equal(t, DEBUG2, moreVerboseLevel(verbose))
+	assert.Equal(t, DEBUG2, moreVerboseLevel(DEBUG))
+}
+
+// restoreLevelTable restores the registered levels after the test, so that the
+// levels registered by the test don't affect the other tests.
+func restoreLevelTable(t *testing.T) {
+	levelTableLock.Lock()
+	table, last := loadLevelTable(), lastLevel
+	levelTableLock.Unlock()
+
+	t.Cleanup(func() {
+		levelTableLock.Lock()
+		defer levelTableLock.Unlock()
+
+		levelTableValue.Store(table)
+		lastLevel = last
+	})
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
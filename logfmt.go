@@ -0,0 +1,180 @@
+package unilogger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LogfmtFormatter is the formatter that writes the message in a logfmt 'key=value' form.
+// The record contains the following keys:
+//	# time - message time formatted using the 'Time' format, if set
+//	# level - message level name
+//	# seq - message sequence id
+//	# logger - name of the logger, if set
+//	# msg - message text
+//	# caller - file and line where the message was logged
+// followed by the message fields in the order of their insertion. The fields with the keys
+// clashing with the above keys are prefixed with the 'fields.', i.e. 'fields.msg' and the repeated
+// keys are suffixed with the number of their repetition, i.e. '!BADKEY.1'.
+// Values containing spaces, '=', quotes or control characters are quoted.
+type LogfmtFormatter struct {
+	// Time defines the format of the message time.
+	Time TimeFormat
+}
+
+var _ Formatter = &LogfmtFormatter{}
+
+// Format implements Formatter interface.
+func (l *LogfmtFormatter) Format(m *Message) ([]byte, error) {
+	b := strings.Builder{}
+	if !m.time.IsZero() {
+		writeLogfmtPair(&b, "time", l.Time.Format(m.time))
+		b.WriteRune(' ')
+	}
+	writeLogfmtPair(&b, "level", m.level.String())
+	b.WriteRune(' ')
+	writeLogfmtPair(&b, "seq", strconv.FormatUint(m.id, 10))
+	if m.logger != "" {
+		b.WriteRune(' ')
+		writeLogfmtPair(&b, "logger", m.logger)
+	}
+	b.WriteRune(' ')
+	writeLogfmtPair(&b, "msg", m.getMessage())
+	if caller := m.shortCaller(); caller != "" {
+		b.WriteRune(' ')
+		writeLogfmtPair(&b, "caller", caller)
+	}
+	keys := fieldKeys(m.fields)
+	for i, field := range m.fields {
+		b.WriteRune(' ')
+		writeLogfmtPair(&b, keys[i], fmt.Sprint(field.Value))
+	}
+	return []byte(b.String()), nil
+}
+
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	b.WriteString(logfmtKey(key))
+	b.WriteRune('=')
+	b.WriteString(quoteFieldValue(value))
+}
+
+// logfmtKey replaces the characters that are not allowed in the logfmt keys with the '_'.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+/**
+
+Decoder
+
+*/
+
+// LogfmtDecoder reads and decodes the logfmt records from the input stream.
+type LogfmtDecoder struct {
+	reader *bufio.Reader
+}
+
+// NewLogfmtDecoder creates new LogfmtDecoder that reads the records from 'r'.
+// Each line of the input is treated as a single record. The length of the lines is not limited.
+func NewLogfmtDecoder(r io.Reader) *LogfmtDecoder {
+	return &LogfmtDecoder{reader: bufio.NewReader(r)}
+}
+
+// Decode reads the next record from the input and returns its key/value pairs
+// in the order of their occurrence. Empty lines are skipped.
+// At the end of the input the function returns io.EOF error.
+func (d *LogfmtDecoder) Decode() ([]Field, error) {
+	for {
+		line, err := d.reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if strings.TrimSpace(line) != "" {
+			return ParseLogfmt(line)
+		}
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+	}
+}
+
+// ParseLogfmt parses single logfmt 'line' into the key/value pairs.
+// The values of the returned fields are the unquoted strings. A key without a value
+// i.e. 'key' or 'key=' has an empty string value.
+func ParseLogfmt(line string) ([]Field, error) {
+	var fields []Field
+	i := 0
+	for {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if i >= len(line) {
+			return fields, nil
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			if line[i] == '"' {
+				return nil, fmt.Errorf("unexpected quote in the key at position: %d", i)
+			}
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			return nil, fmt.Errorf("empty key at position: %d", start)
+		}
+
+		if i >= len(line) || line[i] == ' ' {
+			fields = append(fields, Field{Key: key, Value: ""})
+			continue
+		}
+		// skip the '=' character.
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for ; end < len(line); end++ {
+				if line[end] == '\\' {
+					end++
+					continue
+				}
+				if line[end] == '"' {
+					break
+				}
+			}
+			if end >= len(line) {
+				return nil, fmt.Errorf("unterminated quoted value for the key: '%s'", key)
+			}
+			value, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value for the key: '%s': %v", key, err)
+			}
+			fields = append(fields, Field{Key: key, Value: value})
+			i = end + 1
+			if i < len(line) && line[i] != ' ' {
+				return nil, fmt.Errorf("unexpected character after the quoted value at position: %d", i)
+			}
+			continue
+		}
+
+		start = i
+		for i < len(line) && line[i] != ' ' {
+			i++
+		}
+		fields = append(fields, Field{Key: key, Value: line[start:i]})
+	}
+}
@@ -0,0 +1,134 @@
+package unilogger
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogfmtFormatter tests the BasicLogger with the LogfmtFormatter round-tripped by the LogfmtDecoder.
+func TestLogfmtFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewBasicLogger(&buf, "", 0, WithFormatter(&LogfmtFormatter{}))
+
+	logger.With("user", "john doe", "query", "a=b", "empty", "", "quote", "say \"hi\"\n", "count", 3).Info("first message")
+	firstID := logSequenceID
+	logger.Error("second")
+	secondID := logSequenceID
+
+	decoder := NewLogfmtDecoder(&buf)
+
+	fields, err := decoder.Decode()
+	require.NoError(t, err)
+	require.Len(t, fields, 10)
+
+	keys := []string{"time", "level", "seq", "msg", "caller", "user", "query", "empty", "quote", "count"}
+	for i, key := range keys {
+		assert.Equal(t, key, fields[i].Key)
+	}
+	assert.Equal(t, "INFO", fields[1].Value)
+	assert.Equal(t, strconv.FormatUint(firstID, 10), fields[2].Value)
+	assert.Equal(t, "first message", fields[3].Value)
+	assert.Contains(t, fields[4].Value, "logfmt_test.go:")
+	assert.Equal(t, "john doe", fields[5].Value)
+	assert.Equal(t, "a=b", fields[6].Value)
+	assert.Equal(t, "", fields[7].Value)
+	assert.Equal(t, "say \"hi\"\n", fields[8].Value)
+	assert.Equal(t, "3", fields[9].Value)
+
+	fields, err = decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "ERROR", fields[1].Value)
+	assert.Equal(t, strconv.FormatUint(secondID, 10), fields[2].Value)
+
+	_, err = decoder.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+// TestLogfmtDecoderLongRecord tests decoding the records longer than the default bufio buffers.
+func TestLogfmtDecoderLongRecord(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewBasicLogger(&buf, "", 0, WithFormatter(&LogfmtFormatter{}))
+
+	long := strings.Repeat("long message ", 10000)
+	logger.Info(long)
+	logger.Info("short")
+
+	decoder := NewLogfmtDecoder(&buf)
+	fields, err := decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, long, fields[3].Value)
+
+	fields, err = decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "short", fields[3].Value)
+
+	_, err = decoder.Decode()
+	assert.Equal(t, io.EOF, err)
+
+	// the line endings are stripped and the last line might not end with a new line.
+	decoder = NewLogfmtDecoder(strings.NewReader("a=1\r\n\nb=2"))
+	fields, err = decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, []Field{{Key: "a", Value: "1"}}, fields)
+
+	fields, err = decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, []Field{{Key: "b", Value: "2"}}, fields)
+
+	_, err = decoder.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+// TestLogfmtFormatterReservedKeys tests that the fields don't duplicate the reserved keys.
+func TestLogfmtFormatterReservedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewBasicLogger(&buf, "", 0, WithFormatter(&LogfmtFormatter{}))
+	logger.With("msg", "x", "level", "y", "user", "john", "fields.msg", "z", 1, 2).Info("hi")
+
+	fields, err := NewLogfmtDecoder(&buf).Decode()
+	require.NoError(t, err)
+
+	values := map[string]interface{}{}
+	for _, field := range fields {
+		_, ok := values[field.Key]
+		assert.False(t, ok, field.Key)
+		values[field.Key] = field.Value
+	}
+	assert.Equal(t, "hi", values["msg"])
+	assert.Equal(t, "INFO", values["level"])
+	assert.Equal(t, "x", values["fields.msg"])
+	assert.Equal(t, "y", values["fields.level"])
+	assert.Equal(t, "john", values["user"])
+	assert.Equal(t, "z", values["fields.msg.1"])
+	assert.Equal(t, "1", values["!BADKEY"])
+	assert.Equal(t, "2", values["!BADKEY.1"])
+}
+
+// TestParseLogfmt tests the ParseLogfmt function.
+func TestParseLogfmt(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		fields, err := ParseLogfmt(`a=1  b="two words" flag c= d="esc\"aped"`)
+		require.NoError(t, err)
+		assert.Equal(t, []Field{
+			{Key: "a", Value: "1"},
+			{Key: "b", Value: "two words"},
+			{Key: "flag", Value: ""},
+			{Key: "c", Value: ""},
+			{Key: "d", Value: `esc"aped`},
+		}, fields)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		lines := []string{`=value`, `a="unterminated`, `a="x"y`, `"key"=value`}
+		for _, line := range lines {
+			_, err := ParseLogfmt(line)
+			assert.Error(t, err, line)
+		}
+	})
+}
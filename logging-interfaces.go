@@ -1,5 +1,9 @@
 package unilogger
 
+import (
+	"time"
+)
+
 // SubLogger interface that creates and returns new sub logger.
 type SubLogger interface {
 	SubLogger() LeveledLogger
@@ -10,11 +14,23 @@ type LevelSetter interface {
 	SetLevel(level Level)
 }
 
+// LevelElevator is the interface that allows to temporarily lower the logging level threshold.
+// After the duration 'd' the previous level is restored.
+type LevelElevator interface {
+	ElevateFor(level Level, d time.Duration)
+}
+
 // LevelGetter is the interface used to get current logger level.
 type LevelGetter interface {
 	GetLevel() Level
 }
 
+// MessageLogger is the interface for the loggers that log already created messages.
+// It allows to pass the structured fields, time and caller of the message to the logger.
+type MessageLogger interface {
+	LogMessage(m *Message)
+}
+
 // OutputDepthSetter is the interface that sets the output depth for the logging interface.
 type OutputDepthSetter interface {
 	SetOutputDepth(depth int)
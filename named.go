@@ -0,0 +1,92 @@
+package unilogger
+
+import (
+	"strings"
+	"sync"
+)
+
+// levelTree is the hierarchy of the named loggers levels, keyed by the dotted logger names.
+// It is shared by the logger created with NewBasicLogger and all its descendants.
+type levelTree struct {
+	sync.Mutex
+	root  string
+	nodes map[string]*AtomicLevel
+}
+
+func newLevelTree(root string, level *AtomicLevel) *levelTree {
+	return &levelTree{root: root, nodes: map[string]*AtomicLevel{root: level}}
+}
+
+// node gets or creates the level for the logger 'name' together with all its missing ancestors.
+func (t *levelTree) node(name string) *AtomicLevel {
+	t.Lock()
+	defer t.Unlock()
+
+	if level, ok := t.nodes[name]; ok {
+		return level
+	}
+
+	parent := t.nodes[t.root]
+	relative := name
+	if t.root != "" {
+		relative = strings.TrimPrefix(name, t.root+".")
+	}
+
+	current := t.root
+	for _, segment := range strings.Split(relative, ".") {
+		current = joinLoggerName(current, segment)
+		level, ok := t.nodes[current]
+		if !ok {
+			level = NewChildLevel(parent)
+			t.nodes[current] = level
+		}
+		parent = level
+	}
+	return parent
+}
+
+// joinLoggerName joins the 'parent' and 'name' logger names using a dot.
+func joinLoggerName(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	if name == "" {
+		return parent
+	}
+	return parent + "." + name
+}
+
+// Named creates a child logger with the 'name' appended to the logger name using a dot,
+// i.e. logger named 'db' creates 'db.pool' for Named("pool"). The 'name' itself might contain dots.
+// The named loggers form a hierarchy, where the child logger follows its parent level unless
+// its level is explicitly set using SetLevel method. I.e. setting the level of the 'db' logger
+// affects 'db.pool' and 'db.migrations' loggers but not the 'http' logger.
+// The loggers with the same name share their level.
+func (l *BasicLogger) Named(name string) *BasicLogger {
+	name = strings.Trim(name, ".")
+	if name == "" {
+		return l
+	}
+
+	child := l.clone()
+	child.name = joinLoggerName(l.name, name)
+	child.level = l.tree.node(child.name)
+	child.handler = l.handler.WithName(child.name)
+	return child
+}
+
+// Name gets the logger name.
+func (l *BasicLogger) Name() string {
+	return l.name
+}
+
+// InheritLevel makes the logger follow its parent logger level again, after it was set
+// using SetLevel method. It doesn't change the level of the root logger.
+func (l *BasicLogger) InheritLevel() {
+	l.level.Inherit()
+}
+
+// IsLevelInherited checks if the logger follows its parent logger level.
+func (l *BasicLogger) IsLevelInherited() bool {
+	return l.level.IsInherited()
+}
@@ -0,0 +1,79 @@
+package unilogger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNamed tests the hierarchical named loggers.
+func TestNamed(t *testing.T) {
+	var buf bytes.Buffer
+	root := NewBasicLogger(&buf, "", 0)
+
+	pool := root.Named("db.pool")
+	db := root.Named("db")
+	migrations := db.Named("migrations")
+	http := root.Named("http")
+
+	assert.Equal(t, "db.pool", pool.Name())
+	assert.Equal(t, "db.migrations", migrations.Name())
+	assert.Equal(t, root, root.Named("."))
+
+	t.Run("Inherit", func(t *testing.T) {
+		for _, logger := range []*BasicLogger{pool, db, migrations, http} {
+			assert.Equal(t, INFO, logger.GetLevel())
+		}
+
+		db.SetLevel(DEBUG)
+		assert.Equal(t, DEBUG, pool.GetLevel())
+		assert.Equal(t, DEBUG, migrations.GetLevel())
+		assert.Equal(t, INFO, http.GetLevel())
+		assert.Equal(t, INFO, root.GetLevel())
+
+		root.SetLevel(WARNING)
+		assert.Equal(t, DEBUG, pool.GetLevel())
+		assert.Equal(t, WARNING, http.GetLevel())
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		pool.SetLevel(ERROR)
+		assert.Equal(t, ERROR, root.Named("db").Named("pool").GetLevel())
+		assert.Equal(t, DEBUG, migrations.GetLevel())
+
+		pool.InheritLevel()
+		assert.Equal(t, DEBUG, pool.GetLevel())
+
+		db.InheritLevel()
+		assert.Equal(t, WARNING, pool.GetLevel())
+
+		root.InheritLevel()
+		assert.Equal(t, WARNING, root.GetLevel())
+	})
+
+	t.Run("Output", func(t *testing.T) {
+		buf.Reset()
+		pool.Warning("message")
+		assert.Equal(t, fmt.Sprintf("WARNING|%04x|db.pool: message\n", logSequenceID), buf.String())
+	})
+
+	t.Run("RootName", func(t *testing.T) {
+		api := NewBasicLogger(&buf, "", 0, WithName("api"))
+		child := api.Named("db")
+		assert.Equal(t, "api.db", child.Name())
+
+		api.SetLevel(ERROR)
+		assert.Equal(t, ERROR, child.GetLevel())
+		assert.Equal(t, ERROR, api.Named("db.pool").GetLevel())
+	})
+
+	t.Run("SubLogger", func(t *testing.T) {
+		sub := http.SubLogger().(*BasicLogger)
+		assert.Equal(t, WARNING, sub.GetLevel())
+
+		http.SetLevel(DEBUG2)
+		assert.Equal(t, DEBUG2, sub.GetLevel())
+	})
+}
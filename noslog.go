@@ -0,0 +1,13 @@
+//go:build !go1.21
+// +build !go1.21
+
+package unilogger
+
+// wrapSlog is not supported before go1.21.
+func wrapSlog(wrapper *LoggerWrapper, logger interface{}) bool {
+	return false
+}
+
+func (c *LoggerWrapper) logSlog(level Level, format *string, ln bool, args ...interface{}) {}
+
+func (c *LoggerWrapper) logSlogMessage(m *Message) {}
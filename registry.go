@@ -0,0 +1,392 @@
+package unilogger
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registry is the registry of the named loggers. It allows to list the registered loggers
+// and change their levels at runtime. The registered loggers must implement LevelSetter interface.
+// If they implement also LevelGetter their current level is available in the listing.
+type Registry struct {
+	lock    sync.RWMutex
+	entries map[string]*registryEntry
+}
+
+type registryEntry struct {
+	logger       LevelSetter
+	defaultLevel Level
+	inherited    bool
+
+	// overrides are the active levels set using SetLevelFor, keyed by their ids.
+	overridesLock  sync.Mutex
+	overrides      map[uint64]levelOverride
+	lastOverrideID uint64
+	restore        func()
+
+	// elevations are the expiration times of the logger level elevations made using SetLevelFor,
+	// keyed by the AtomicLevel elevation ids, so that they could be cancelled by the permanent changes.
+	elevations map[uint64]time.Time
+}
+
+// levelOverride is the temporary level set using SetLevelFor along with its expiration timer.
+type levelOverride struct {
+	level Level
+	timer *time.Timer
+}
+
+// LoggerInfo contains the information about the registered logger.
+type LoggerInfo struct {
+	// Name is the name of the logger in the registry.
+	Name string
+	// Level is the current level of the logger. It is UNKNOWN if the logger doesn't implement LevelGetter.
+	Level Level
+	// DefaultLevel is the level of the logger at the time of registration.
+	DefaultLevel Level
+}
+
+// levelInheritor is the interface implemented by the loggers that might follow their parent level.
+type levelInheritor interface {
+	IsLevelInherited() bool
+	InheritLevel()
+}
+
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry gets the package default registry used by the Register, SetLevel,
+// ResetLevel and Loggers functions.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// NewRegistry creates new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{entries: map[string]*registryEntry{}}
+}
+
+// Register registers the 'logger' under the 'name'. The current level of the logger
+// is stored as its default level. The function returns error if the name is already registered.
+func (r *Registry) Register(name string, logger LevelSetter) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.entries[name]; ok {
+		return fmt.Errorf("logger: '%s' is already registered", name)
+	}
+	entry := &registryEntry{logger: logger, defaultLevel: UNKNOWN}
+	if level, ok := baseLevel(logger); ok {
+		entry.defaultLevel = level
+	}
+	if inheritor, ok := logger.(levelInheritor); ok {
+		entry.inherited = inheritor.IsLevelInherited()
+	}
+	r.entries[name] = entry
+	return nil
+}
+
+// MustRegister registers the 'logger' under the 'name'. Panics if the name is already registered.
+func (r *Registry) MustRegister(name string, logger LevelSetter) {
+	if err := r.Register(name, logger); err != nil {
+		panic(err)
+	}
+}
+
+// Unregister removes the logger with given 'name' from the registry.
+// Returns false if the logger was not registered.
+func (r *Registry) Unregister(name string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, ok := r.entries[name]
+	delete(r.entries, name)
+	return ok
+}
+
+// Get gets the logger registered under the 'name'.
+func (r *Registry) Get(name string) (LevelSetter, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	entry, ok := r.entries[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.logger, true
+}
+
+// SetLevel sets the 'level' for all the loggers with the names matching the 'pattern'.
+// The pattern syntax is the same as in the path.Match function i.e.: 'storage.*'.
+// The pending temporary level changes set using SetLevelFor are cancelled, so that the 'level'
+// is not replaced when they expire.
+// Returns the number of the changed loggers or an error if the pattern is malformed.
+func (r *Registry) SetLevel(pattern string, level Level) (int, error) {
+	entries, err := r.match(pattern)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		entry.set(func() {
+			entry.logger.SetLevel(level)
+		})
+	}
+	return len(entries), nil
+}
+
+// SetLevelFor sets the 'level' for all the loggers with the names matching the 'pattern'
+// for the 'ttl' duration. Afterwards the level that the loggers had before the change is restored.
+// For the overlapping changes the level of the latest active change is used and the level that
+// the loggers had before the first of them is restored after the last one expires.
+// If the 'level' is lower than the level of the logger using the AtomicLevel, ignoring its active elevations,
+// the AtomicLevel is elevated (see AtomicLevel.ElevateFor), so that the overlapping changes are resolved correctly.
+// Returns the number of the changed loggers or an error if the pattern is malformed.
+func (r *Registry) SetLevelFor(pattern string, level Level, ttl time.Duration) (int, error) {
+	entries, err := r.match(pattern)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if atomicLevel, ok := loggerAtomicLevel(entry.logger); ok && level.Severity() <= atomicLevel.BaseLevel().Severity() {
+			entry.elevate(atomicLevel, level, ttl)
+			continue
+		}
+		entry.override(level, ttl)
+	}
+	return len(entries), nil
+}
+
+// ResetLevel resets the level of all the loggers with names matching the 'pattern' to their
+// default level. The loggers that were following their parent level at the time of registration
+// follows it again. The pending temporary level changes set using SetLevelFor are cancelled.
+// Returns the number of the reset loggers or an error if the pattern is malformed.
+func (r *Registry) ResetLevel(pattern string) (int, error) {
+	entries, err := r.match(pattern)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		entry.set(entry.reset)
+	}
+	return len(entries), nil
+}
+
+// Loggers lists the registered loggers sorted by their names.
+func (r *Registry) Loggers() []LoggerInfo {
+	infos, _ := r.LoggersMatching("*")
+	return infos
+}
+
+// LoggersMatching lists the registered loggers with the names matching the 'pattern' sorted by their names.
+// Returns error if the pattern is malformed.
+func (r *Registry) LoggersMatching(pattern string) ([]LoggerInfo, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	infos := make([]LoggerInfo, 0, len(r.entries))
+	for name, entry := range r.entries {
+		if ok, _ := path.Match(pattern, name); !ok {
+			continue
+		}
+		info := LoggerInfo{Name: name, Level: UNKNOWN, DefaultLevel: entry.defaultLevel}
+		if getter, ok := entry.logger.(LevelGetter); ok {
+			info.Level = getter.GetLevel()
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos, nil
+}
+
+// override sets the 'level' of the entry logger for the 'ttl' duration. The overlapping overrides
+// are tracked, so that the level from before the first override is restored after the last one expires.
+func (e *registryEntry) override(level Level, ttl time.Duration) {
+	e.overridesLock.Lock()
+	defer e.overridesLock.Unlock()
+
+	if len(e.overrides) == 0 {
+		e.overrides = map[uint64]levelOverride{}
+		e.restore = e.levelRestorer()
+	}
+	e.lastOverrideID++
+	id := e.lastOverrideID
+	e.logger.SetLevel(level)
+
+	timer := time.AfterFunc(ttl, func() {
+		e.overridesLock.Lock()
+		defer e.overridesLock.Unlock()
+
+		if _, ok := e.overrides[id]; !ok {
+			// the override was cancelled by a permanent level change.
+			return
+		}
+		delete(e.overrides, id)
+		if len(e.overrides) == 0 {
+			e.restore()
+			e.restore = nil
+			return
+		}
+		// the latest of the remaining overrides is used if the expired one was the latest.
+		var latest uint64
+		for overrideID := range e.overrides {
+			if overrideID > latest {
+				latest = overrideID
+			}
+		}
+		if id > latest {
+			e.logger.SetLevel(e.overrides[latest].level)
+		}
+	})
+	e.overrides[id] = levelOverride{level: level, timer: timer}
+}
+
+// elevate elevates the 'atomicLevel' of the entry logger to the 'level' for the 'ttl' duration
+// and records the elevation, so that it could be cancelled by the permanent level changes.
+func (e *registryEntry) elevate(atomicLevel *AtomicLevel, level Level, ttl time.Duration) {
+	e.overridesLock.Lock()
+	defer e.overridesLock.Unlock()
+
+	now := time.Now()
+	if e.elevations == nil {
+		e.elevations = map[uint64]time.Time{}
+	}
+	// the expired elevations are no longer tracked.
+	for id, expires := range e.elevations {
+		if now.After(expires) {
+			delete(e.elevations, id)
+		}
+	}
+	e.elevations[atomicLevel.elevate(level, ttl)] = now.Add(ttl)
+}
+
+// set cancels the pending overrides and elevations of the entry and calls the 'setLevel' function
+// that changes the level of the entry logger permanently.
+func (e *registryEntry) set(setLevel func()) {
+	e.overridesLock.Lock()
+	defer e.overridesLock.Unlock()
+
+	for id, override := range e.overrides {
+		override.timer.Stop()
+		delete(e.overrides, id)
+	}
+	e.restore = nil
+	if atomicLevel, ok := loggerAtomicLevel(e.logger); ok {
+		for id := range e.elevations {
+			atomicLevel.cancelElevation(id)
+			delete(e.elevations, id)
+		}
+	}
+	setLevel()
+}
+
+// reset sets the default level of the entry logger or makes it follow its parent level again.
+func (e *registryEntry) reset() {
+	if inheritor, ok := e.logger.(levelInheritor); ok && e.inherited {
+		inheritor.InheritLevel()
+		return
+	}
+	if e.defaultLevel != UNKNOWN {
+		e.logger.SetLevel(e.defaultLevel)
+	}
+}
+
+// levelRestorer returns the function that restores current base level of the entry logger.
+func (e *registryEntry) levelRestorer() func() {
+	if inheritor, ok := e.logger.(levelInheritor); ok && inheritor.IsLevelInherited() {
+		return inheritor.InheritLevel
+	}
+	level, ok := baseLevel(e.logger)
+	if !ok {
+		return func() {}
+	}
+	return func() {
+		e.logger.SetLevel(level)
+	}
+}
+
+// atomicLeveler is the interface implemented by the loggers using the AtomicLevel.
+type atomicLeveler interface {
+	AtomicLevel() *AtomicLevel
+}
+
+// baseLevel gets the level of the 'logger' without its active elevations. For the loggers
+// that don't use the AtomicLevel it is the level got using the LevelGetter interface.
+func baseLevel(logger LevelSetter) (Level, bool) {
+	if atomicLevel, ok := loggerAtomicLevel(logger); ok {
+		return atomicLevel.BaseLevel(), true
+	}
+	getter, ok := logger.(LevelGetter)
+	if !ok {
+		return UNKNOWN, false
+	}
+	return getter.GetLevel(), true
+}
+
+// loggerAtomicLevel gets the AtomicLevel used by the 'logger' or by the logger wrapped by the LoggerWrapper.
+func loggerAtomicLevel(logger LevelSetter) (*AtomicLevel, bool) {
+	var unwrapped interface{} = logger
+	if wrapper, ok := logger.(*LoggerWrapper); ok {
+		unwrapped = wrapper.logger
+	}
+	leveler, ok := unwrapped.(atomicLeveler)
+	if !ok {
+		return nil, false
+	}
+	return leveler.AtomicLevel(), true
+}
+
+func (r *Registry) match(pattern string) ([]*registryEntry, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var entries []*registryEntry
+	for name, entry := range r.entries {
+		if ok, _ := path.Match(pattern, name); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Register registers the 'logger' under the 'name' in the default registry.
+func Register(name string, logger LevelSetter) error {
+	return defaultRegistry.Register(name, logger)
+}
+
+// MustRegister registers the 'logger' under the 'name' in the default registry.
+// Panics if the name is already registered.
+func MustRegister(name string, logger LevelSetter) {
+	defaultRegistry.MustRegister(name, logger)
+}
+
+// Unregister removes the logger with given 'name' from the default registry.
+func Unregister(name string) bool {
+	return defaultRegistry.Unregister(name)
+}
+
+// SetLevel sets the 'level' for the loggers from the default registry with the names matching the 'pattern'.
+func SetLevel(pattern string, level Level) (int, error) {
+	return defaultRegistry.SetLevel(pattern, level)
+}
+
+// ResetLevel resets the level of the loggers from the default registry with the names matching
+// the 'pattern' to their default levels.
+func ResetLevel(pattern string) (int, error) {
+	return defaultRegistry.ResetLevel(pattern)
+}
+
+// Loggers lists the loggers registered in the default registry.
+func Loggers() []LoggerInfo {
+	return defaultRegistry.Loggers()
+}
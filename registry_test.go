@@ -0,0 +1,204 @@
+package unilogger
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegistry tests the logger Registry.
+func TestRegistry(t *testing.T) {
+	root := NewBasicLogger(ioutil.Discard, "", 0)
+	storage := root.Named("storage")
+	storageDB := root.Named("storage.db")
+	http := root.Named("http")
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register("storage", storage))
+	require.NoError(t, registry.Register("storage.db", storageDB))
+	require.NoError(t, registry.Register("http", http))
+	require.NoError(t, registry.Register("wrapped", MustGetLoggerWrapper(&stdlogger{})))
+
+	assert.Error(t, registry.Register("http", http))
+	assert.Panics(t, func() { registry.MustRegister("http", http) })
+
+	t.Run("SetLevel", func(t *testing.T) {
+		n, err := registry.SetLevel("storage.*", DEBUG2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, DEBUG2, storageDB.GetLevel())
+		assert.Equal(t, INFO, storage.GetLevel())
+
+		n, err = registry.SetLevel("*", WARNING)
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+		assert.Equal(t, WARNING, http.GetLevel())
+
+		_, err = registry.SetLevel("[", WARNING)
+		assert.Error(t, err)
+	})
+
+	t.Run("Loggers", func(t *testing.T) {
+		infos := registry.Loggers()
+		assert.Equal(t, []LoggerInfo{
+			{Name: "http", Level: WARNING, DefaultLevel: INFO},
+			{Name: "storage", Level: WARNING, DefaultLevel: INFO},
+			{Name: "storage.db", Level: WARNING, DefaultLevel: INFO},
+			{Name: "wrapped", Level: UNKNOWN, DefaultLevel: UNKNOWN},
+		}, infos)
+	})
+
+	t.Run("ResetLevel", func(t *testing.T) {
+		n, err := registry.ResetLevel("*")
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+		assert.True(t, storageDB.IsLevelInherited())
+
+		root.SetLevel(ERROR)
+		assert.Equal(t, ERROR, storageDB.GetLevel())
+	})
+
+	t.Run("Unregister", func(t *testing.T) {
+		assert.True(t, registry.Unregister("wrapped"))
+		assert.False(t, registry.Unregister("wrapped"))
+
+		_, ok := registry.Get("wrapped")
+		assert.False(t, ok)
+
+		logger, ok := registry.Get("http")
+		assert.True(t, ok)
+		assert.Equal(t, http, logger)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		MustRegister("test.default", NewBasicLogger(ioutil.Discard, "", 0))
+		defer Unregister("test.default")
+
+		n, err := SetLevel("test.*", ERROR)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Contains(t, Loggers(), LoggerInfo{Name: "test.default", Level: ERROR, DefaultLevel: INFO})
+
+		_, err = ResetLevel("test.default")
+		require.NoError(t, err)
+		assert.Contains(t, DefaultRegistry().Loggers(), LoggerInfo{Name: "test.default", Level: INFO, DefaultLevel: INFO})
+		assert.Error(t, Register("test.default", NewBasicLogger(ioutil.Discard, "", 0)))
+	})
+}
+
+// TestRegistrySetLevelFor tests the overlapping temporary level changes.
+func TestRegistrySetLevelFor(t *testing.T) {
+	t.Run("Raised", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+		registry := NewRegistry()
+		registry.MustRegister("logger", logger)
+
+		_, err := registry.SetLevelFor("logger", ERROR, 20*time.Millisecond)
+		require.NoError(t, err)
+		_, err = registry.SetLevelFor("logger", CRITICAL, 60*time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, CRITICAL, logger.GetLevel())
+
+		time.Sleep(40 * time.Millisecond)
+		assert.Equal(t, CRITICAL, logger.GetLevel())
+
+		waitFor(t, func() bool { return logger.GetLevel() == INFO })
+		time.Sleep(40 * time.Millisecond)
+		assert.Equal(t, INFO, logger.GetLevel())
+	})
+
+	t.Run("LatestExpired", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+		registry := NewRegistry()
+		registry.MustRegister("logger", logger)
+
+		_, err := registry.SetLevelFor("logger", ERROR, 60*time.Millisecond)
+		require.NoError(t, err)
+		_, err = registry.SetLevelFor("logger", CRITICAL, 20*time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, CRITICAL, logger.GetLevel())
+
+		waitFor(t, func() bool { return logger.GetLevel() == ERROR })
+		waitFor(t, func() bool { return logger.GetLevel() == INFO })
+	})
+
+	t.Run("Inherited", func(t *testing.T) {
+		root := NewBasicLogger(ioutil.Discard, "", 0)
+		db := root.Named("db")
+		registry := NewRegistry()
+		registry.MustRegister("db", db)
+
+		_, err := registry.SetLevelFor("db", ERROR, 20*time.Millisecond)
+		require.NoError(t, err)
+		_, err = registry.SetLevelFor("db", WARNING, 40*time.Millisecond)
+		require.NoError(t, err)
+		assert.False(t, db.IsLevelInherited())
+
+		waitFor(t, func() bool { return db.IsLevelInherited() })
+		assert.Equal(t, INFO, db.GetLevel())
+	})
+	t.Run("SetLevel", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+		registry := NewRegistry()
+		registry.MustRegister("logger", logger)
+
+		_, err := registry.SetLevelFor("logger", ERROR, 30*time.Millisecond)
+		require.NoError(t, err)
+		_, err = registry.SetLevel("logger", WARNING)
+		require.NoError(t, err)
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, WARNING, logger.GetLevel())
+	})
+
+	t.Run("ResetLevel", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+		registry := NewRegistry()
+		registry.MustRegister("logger", logger)
+		logger.SetLevel(WARNING)
+
+		_, err := registry.SetLevelFor("logger", ERROR, 30*time.Millisecond)
+		require.NoError(t, err)
+		_, err = registry.ResetLevel("logger")
+		require.NoError(t, err)
+		assert.Equal(t, INFO, logger.GetLevel())
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, INFO, logger.GetLevel())
+	})
+	t.Run("SetLevelElevated", func(t *testing.T) {
+		logger := NewBasicLogger(ioutil.Discard, "", 0)
+		registry := NewRegistry()
+		registry.MustRegister("logger", logger)
+
+		_, err := registry.SetLevelFor("logger", DEBUG3, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, DEBUG3, logger.GetLevel())
+
+		_, err = registry.SetLevel("logger", WARNING)
+		require.NoError(t, err)
+		assert.Equal(t, WARNING, logger.GetLevel())
+		assert.False(t, logger.AtomicLevel().IsElevated())
+	})
+
+	t.Run("ResetLevelElevated", func(t *testing.T) {
+		root := NewBasicLogger(ioutil.Discard, "", 0)
+		db := root.Named("db")
+		registry := NewRegistry()
+		registry.MustRegister("db", db)
+
+		_, err := registry.SetLevelFor("db", DEBUG2, time.Hour)
+		require.NoError(t, err)
+		_, err = registry.SetLevelFor("db", DEBUG, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, DEBUG2, db.GetLevel())
+
+		_, err = registry.ResetLevel("db")
+		require.NoError(t, err)
+		assert.Equal(t, INFO, db.GetLevel())
+		assert.True(t, db.IsLevelInherited())
+	})
+}
@@ -0,0 +1,153 @@
+package unilogger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// ReopenFile is the io.Writer that writes to the file at given path and reopens it when the file is
+// moved or removed, i.e. by the external 'logrotate' performing the rename based rotation.
+// The file is reopened on SIGHUP signal, using the Reopen method or when the file at the path
+// is no longer the opened file. The writes are serialized with the reopening, so that each write
+// goes either to the previous or to the new file. If the file couldn't be reopened, the previous
+// file is still used. The SIGHUP signal is not supported on js and wasip1.
+type ReopenFile struct {
+	path          string
+	checkInterval time.Duration
+
+	lock      sync.Mutex
+	file      *os.File
+	lastCheck time.Time
+	closed    bool
+
+	signals  chan os.Signal
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+var _ io.WriteCloser = &ReopenFile{}
+
+var errReopenFileClosed = errors.New("reopen file: already closed")
+
+// NewReopenFile opens the file at 'path' for appending, creating it if it doesn't exist.
+// The file is checked for being moved or removed at most once per 'checkInterval' during the writes.
+// If the 'checkInterval' is zero, the file is checked on each write and if it is negative, the file
+// is not checked at all, so that it is reopened only on SIGHUP or using the Reopen method.
+func NewReopenFile(path string, checkInterval time.Duration) (*ReopenFile, error) {
+	f := &ReopenFile{
+		path:          path,
+		checkInterval: checkInterval,
+		signals:       make(chan os.Signal, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	file, err := openAppend(path)
+	if err != nil {
+		return nil, err
+	}
+	f.file, f.lastCheck = file, time.Now()
+
+	notifyHangup(f.signals)
+	go f.handleSignals()
+	return f, nil
+}
+
+// Write implements io.Writer interface.
+func (f *ReopenFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return 0, errReopenFileClosed
+	}
+	if f.checkInterval >= 0 {
+		if now := time.Now(); now.Sub(f.lastCheck) >= f.checkInterval {
+			f.lastCheck = now
+			if f.moved() {
+				f.reopen()
+			}
+		}
+	}
+	return f.file.Write(p)
+}
+
+// Reopen closes the current file and opens the file at the path again.
+// If the file couldn't be opened, the error is returned and the current file is still used.
+func (f *ReopenFile) Reopen() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return errReopenFileClosed
+	}
+	return f.reopen()
+}
+
+// Name gets the path of the file.
+func (f *ReopenFile) Name() string {
+	return f.path
+}
+
+// Close stops handling the signals and closes the file.
+func (f *ReopenFile) Close() error {
+	f.stopOnce.Do(func() {
+		signal.Stop(f.signals)
+		close(f.stop)
+	})
+	<-f.done
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	return f.file.Close()
+}
+
+// moved checks if the file at the path is not the opened file.
+func (f *ReopenFile) moved() bool {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return true
+	}
+	current, err := f.file.Stat()
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(info, current)
+}
+
+// reopen opens the new file before closing the current one, so that no writes are lost
+// if the file couldn't be opened.
+func (f *ReopenFile) reopen() error {
+	file, err := openAppend(f.path)
+	if err != nil {
+		return err
+	}
+	previous := f.file
+	f.file = file
+	return previous.Close()
+}
+
+func (f *ReopenFile) handleSignals() {
+	defer close(f.done)
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-f.signals:
+			f.Reopen()
+		}
+	}
+}
+
+func openAppend(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+}
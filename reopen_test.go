@@ -0,0 +1,153 @@
+package unilogger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReopenFile tests reopening the file moved by the external rotation.
+func TestReopenFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	t.Run("Moved", func(t *testing.T) {
+		f, err := NewReopenFile(path, 0)
+		require.NoError(t, err)
+		defer f.Close()
+		assert.Equal(t, path, f.Name())
+
+		_, err = f.Write([]byte("first\n"))
+		require.NoError(t, err)
+		require.NoError(t, os.Rename(path, path+".1"))
+
+		_, err = f.Write([]byte("second\n"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "first\n", readLogFile(t, path+".1"))
+		assert.Equal(t, "second\n", readLogFile(t, path))
+
+		require.NoError(t, os.Remove(path))
+		_, err = f.Write([]byte("third\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "third\n", readLogFile(t, path))
+	})
+
+	t.Run("Unchecked", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(path))
+		f, err := NewReopenFile(path, -1)
+		require.NoError(t, err)
+
+		require.NoError(t, os.Rename(path, path+".2"))
+		_, err = f.Write([]byte("moved\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "moved\n", readLogFile(t, path+".2"))
+
+		require.NoError(t, f.Reopen())
+		_, err = f.Write([]byte("reopened\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "reopened\n", readLogFile(t, path))
+
+		require.NoError(t, f.Close())
+		require.NoError(t, f.Close())
+		_, err = f.Write([]byte("closed\n"))
+		assert.Error(t, err)
+		assert.Error(t, f.Reopen())
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := NewReopenFile(filepath.Join(dir, "missing", "app.log"), 0)
+		assert.Error(t, err)
+	})
+}
+
+// TestReopenFileSignal tests reopening the ReopenFile on SIGHUP.
+func TestReopenFileSignal(t *testing.T) {
+	skipWithoutHangup(t)
+
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewReopenFile(path, -1)
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.NoError(t, os.Rename(path, path+".1"))
+	sendHangup(t)
+	waitFor(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	})
+
+	_, err = f.Write([]byte("signaled\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "signaled\n", readLogFile(t, path))
+}
+
+// TestReopenFileLogger tests that no lines are lost or duplicated while the logger output is rotated.
+func TestReopenFileLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewReopenFile(path, time.Millisecond)
+	require.NoError(t, err)
+	logger := NewBasicLogger(f, "", 0)
+
+	const writers, records = 4, 250
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < records; j++ {
+				logger.Infof("writer=%d record=%d", i, j)
+			}
+		}(i)
+	}
+	for i := 0; i < 5; i++ {
+		time.Sleep(2 * time.Millisecond)
+		// the file might not be reopened yet if no record was written since the previous rename.
+		if err := os.Rename(path, fmt.Sprintf("%s.%d", path, i)); err != nil {
+			require.True(t, os.IsNotExist(err), err)
+		}
+	}
+	wg.Wait()
+	require.NoError(t, f.Close())
+
+	names, err := filepath.Glob(path + "*")
+	require.NoError(t, err)
+
+	var lines []string
+	for _, name := range names {
+		for _, line := range strings.Split(strings.TrimSpace(readLogFile(t, name)), "\n") {
+			if line != "" {
+				lines = append(lines, line[strings.Index(line, ": ")+2:])
+			}
+		}
+	}
+	sort.Strings(lines)
+
+	var expected []string
+	for i := 0; i < writers; i++ {
+		for j := 0; j < records; j++ {
+			expected = append(expected, fmt.Sprintf("writer=%d record=%d", i, j))
+		}
+	}
+	sort.Strings(expected)
+	assert.Equal(t, expected, lines)
+}
@@ -0,0 +1,375 @@
+package unilogger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation defines the time based rotation of the RotatingFile.
+type Rotation int
+
+// Following time based rotations are supported by the RotatingFile.
+const (
+	RotateNever Rotation = iota
+	RotateHourly
+	RotateDaily
+)
+
+// rotatedTimeLayout is the layout of the time in the names of the log files.
+const rotatedTimeLayout = "2006-01-02T15-04-05.000"
+
+var errRotatingFileClosed = errors.New("rotating file: already closed")
+
+// RotatingFile is the io.Writer that writes to the log files rotated by their size and time.
+// The log files are named after the 'Filename' with the time of their creation,
+// i.e. the 'app.log' files are 'app-2019-07-01T12-30-00.000.log', and the 'Filename' itself
+// is the symlink to the current log file. If the 'Filename' exists and it is not a symlink
+// it is not replaced. The symlink is not created if it is not supported by the system.
+// The files are opened on the first write, where the current log file is reused
+// if it could be still written, i.e. after the process restart.
+// The rotated files are compressed and the old files are removed in a background goroutine.
+type RotatingFile struct {
+	// Filename is the path of the current log file symlink. The log files are created
+	// in the same directory, which is created if it doesn't exist.
+	Filename string
+	// MaxSize is the maximal size of the log file in bytes. Zero means no limit.
+	MaxSize int64
+	// Rotation is the time based rotation. By default the files are not rotated by time.
+	Rotation Rotation
+	// MaxBackups is the maximal number of the rotated files to keep. Zero means no limit.
+	MaxBackups int
+	// MaxAge is the maximal age of the rotated files to keep, counted from their rotation.
+	// Zero means no limit.
+	MaxAge time.Duration
+	// Compress enables the gzip compression of the rotated files.
+	Compress bool
+	// UTC defines if the times in the file names and the time based rotation use UTC.
+	UTC bool
+	// Clock is the function that gets the current time. By default time.Now is used.
+	Clock func() time.Time
+
+	lock    sync.Mutex
+	file    *os.File
+	current string
+	size    int64
+	created time.Time
+	closed  bool
+
+	cleanupOnce sync.Once
+	cleanup     chan struct{}
+	cleanupDone chan struct{}
+}
+
+var _ io.WriteCloser = &RotatingFile{}
+
+// Write implements io.Writer interface. If writing the 'p' would exceed the MaxSize
+// or the rotation time passed, the file is rotated before writing.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return 0, errRotatingFileClosed
+	}
+	if r.file == nil {
+		if err := r.openExisting(len(p)); err != nil {
+			return 0, err
+		}
+	} else if r.shouldRotate(len(p)) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate closes the current log file and opens a new one.
+func (r *RotatingFile) Rotate() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return errRotatingFileClosed
+	}
+	return r.rotate()
+}
+
+// Close closes the current log file and waits until the background compression and removal
+// of the rotated files is done. The RotatingFile could not be used afterwards.
+func (r *RotatingFile) Close() error {
+	r.lock.Lock()
+	if r.closed {
+		r.lock.Unlock()
+		return nil
+	}
+	r.closed = true
+
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.lock.Unlock()
+
+	if r.cleanup != nil {
+		close(r.cleanup)
+		<-r.cleanupDone
+	}
+	return err
+}
+
+func (r *RotatingFile) now() time.Time {
+	now := time.Now
+	if r.Clock != nil {
+		now = r.Clock
+	}
+	if r.UTC {
+		return now().UTC()
+	}
+	return now().Local()
+}
+
+func (r *RotatingFile) shouldRotate(n int) bool {
+	if r.MaxSize > 0 && r.size > 0 && r.size+int64(n) > r.MaxSize {
+		return true
+	}
+	return !r.samePeriod(r.created, r.now())
+}
+
+// samePeriod checks if the times 'a' and 'b' are in the same rotation period.
+func (r *RotatingFile) samePeriod(a, b time.Time) bool {
+	switch r.Rotation {
+	case RotateHourly:
+		return a.Format("2006010215") == b.Format("2006010215")
+	case RotateDaily:
+		return a.Format("20060102") == b.Format("20060102")
+	}
+	return true
+}
+
+// openExisting opens the current log file pointed by the symlink if it could be still written.
+// Otherwise it opens a new log file.
+func (r *RotatingFile) openExisting(n int) error {
+	if target, err := os.Readlink(r.Filename); err == nil {
+		path := filepath.Join(filepath.Dir(r.Filename), filepath.Base(target))
+		if created, compressed, ok := r.parseName(filepath.Base(path)); ok && !compressed {
+			if info, err := os.Stat(path); err == nil {
+				r.created, r.size = created, info.Size()
+				if !r.shouldRotate(n) {
+					if f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644); err == nil {
+						r.file, r.current = f, filepath.Base(path)
+						r.startCleanup()
+						return nil
+					}
+				}
+			}
+		}
+	}
+	return r.open()
+}
+
+// open creates new log file and points the symlink to it.
+func (r *RotatingFile) open() error {
+	dir := filepath.Dir(r.Filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	now := r.now()
+	prefix, ext := r.nameParts()
+	base := prefix + now.Format(rotatedTimeLayout)
+	name := base + ext
+	for i := 1; ; i++ {
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			r.file, r.current, r.size, r.created = f, name, 0, now
+			break
+		}
+		if !os.IsExist(err) {
+			return err
+		}
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+
+	r.link(name)
+	r.startCleanup()
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			return err
+		}
+		r.file = nil
+	}
+	return r.open()
+}
+
+// link atomically points the 'Filename' symlink to the log file 'name'.
+func (r *RotatingFile) link(name string) {
+	if info, err := os.Lstat(r.Filename); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		return
+	}
+	tmp := r.Filename + ".link"
+	os.Remove(tmp)
+	if err := os.Symlink(name, tmp); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, r.Filename); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// nameParts gets the prefix and the extension of the log file names.
+func (r *RotatingFile) nameParts() (prefix, ext string) {
+	base := filepath.Base(r.Filename)
+	ext = filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// parseName parses the creation time of the log file 'name'.
+func (r *RotatingFile) parseName(name string) (created time.Time, compressed, ok bool) {
+	prefix, ext := r.nameParts()
+	if strings.HasSuffix(name, ".gz") {
+		name, compressed = strings.TrimSuffix(name, ".gz"), true
+	}
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+		return time.Time{}, false, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+	if len(stamp) < len(rotatedTimeLayout) {
+		return time.Time{}, false, false
+	}
+
+	location := time.Local
+	if r.UTC {
+		location = time.UTC
+	}
+	created, err := time.ParseInLocation(rotatedTimeLayout, stamp[:len(rotatedTimeLayout)], location)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+	return created, compressed, true
+}
+
+/**
+
+Cleanup
+
+*/
+
+// startCleanup requests the compression and removal of the rotated files in the background goroutine.
+func (r *RotatingFile) startCleanup() {
+	if r.MaxBackups <= 0 && r.MaxAge <= 0 && !r.Compress {
+		return
+	}
+	r.cleanupOnce.Do(func() {
+		r.cleanup = make(chan struct{}, 1)
+		r.cleanupDone = make(chan struct{})
+		go func() {
+			defer close(r.cleanupDone)
+			for range r.cleanup {
+				r.cleanupFiles()
+			}
+		}()
+	})
+	select {
+	case r.cleanup <- struct{}{}:
+	default:
+	}
+}
+
+type rotatedFile struct {
+	name       string
+	created    time.Time
+	compressed bool
+}
+
+// cleanupFiles compresses the rotated files and removes the ones exceeding the MaxBackups or MaxAge.
+func (r *RotatingFile) cleanupFiles() {
+	dir := filepath.Dir(r.Filename)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	// the current file is read after listing the directory, so that the files created
+	// by the rotation in the meantime are not listed.
+	r.lock.Lock()
+	current := r.current
+	r.lock.Unlock()
+	var files []rotatedFile
+	for _, info := range infos {
+		if info.IsDir() || info.Name() == current {
+			continue
+		}
+		if created, compressed, ok := r.parseName(info.Name()); ok {
+			files = append(files, rotatedFile{name: info.Name(), created: created, compressed: compressed})
+		}
+	}
+	// the newest files first.
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].created.After(files[j].created)
+	})
+
+	// the files are aged by their rotation time, which is the creation time of the next file,
+	// so that the files written for a long time are not removed right after the rotation.
+	now := r.now()
+	rotated := now
+	if created, _, ok := r.parseName(current); ok && created.Before(now) {
+		rotated = created
+	}
+	cutoff := now.Add(-r.MaxAge)
+	for i, file := range files {
+		path := filepath.Join(dir, file.name)
+		expired := rotated.Before(cutoff)
+		rotated = file.created
+		if (r.MaxBackups > 0 && i >= r.MaxBackups) || (r.MaxAge > 0 && expired) {
+			os.Remove(path)
+			continue
+		}
+		if r.Compress && !file.compressed {
+			compressFile(path)
+		}
+	}
+}
+
+// compressFile compresses the file at 'path' into the 'path.gz' file and removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,256 @@
+package unilogger
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is the manually advanced clock.
+type testClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// logFiles gets the sorted names of the log files in the 'dir', without the symlink.
+func logFiles(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, info := range infos {
+		if info.Mode()&os.ModeSymlink == 0 {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readLogFile(t *testing.T, path string) string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	if !strings.HasSuffix(path, ".gz") {
+		data, err := ioutil.ReadAll(f)
+		require.NoError(t, err)
+		return string(data)
+	}
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// TestRotatingFile tests the size and time based rotation.
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clock := &testClock{now: time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC)}
+	path := filepath.Join(dir, "logs", "app.log")
+
+	t.Run("Size", func(t *testing.T) {
+		r := &RotatingFile{Filename: path, MaxSize: 10, UTC: true, Clock: clock.Now}
+		for _, line := range []string{"first\n", "second\n", "third\n", "a very long line\n"} {
+			_, err := r.Write([]byte(line))
+			require.NoError(t, err)
+			clock.Add(time.Millisecond)
+		}
+		require.NoError(t, r.Close())
+
+		files := logFiles(t, filepath.Join(dir, "logs"))
+		assert.Equal(t, []string{
+			"app-2019-07-01T12-30-00.000.log",
+			"app-2019-07-01T12-30-00.001.log",
+			"app-2019-07-01T12-30-00.002.log",
+			"app-2019-07-01T12-30-00.003.log",
+		}, files)
+		assert.Equal(t, "first\n", readLogFile(t, filepath.Join(dir, "logs", files[0])))
+		assert.Equal(t, "a very long line\n", readLogFile(t, filepath.Join(dir, "logs", files[3])))
+
+		target, err := os.Readlink(path)
+		require.NoError(t, err)
+		assert.Equal(t, files[3], target)
+		assert.Equal(t, "a very long line\n", readLogFile(t, path))
+
+		_, err = r.Write([]byte("closed"))
+		assert.Error(t, err)
+	})
+
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "logs")))
+
+	t.Run("Time", func(t *testing.T) {
+		r := &RotatingFile{Filename: path, Rotation: RotateHourly, UTC: true, Clock: clock.Now}
+		defer r.Close()
+
+		_, err := r.Write([]byte("first\n"))
+		require.NoError(t, err)
+		clock.Add(10 * time.Minute)
+		_, err = r.Write([]byte("second\n"))
+		require.NoError(t, err)
+		assert.Len(t, logFiles(t, filepath.Join(dir, "logs")), 1)
+
+		clock.Add(30 * time.Minute)
+		_, err = r.Write([]byte("third\n"))
+		require.NoError(t, err)
+		assert.Len(t, logFiles(t, filepath.Join(dir, "logs")), 2)
+		assert.Equal(t, "third\n", readLogFile(t, path))
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		r := &RotatingFile{Filename: path, Rotation: RotateHourly, UTC: true, Clock: clock.Now}
+		_, err := r.Write([]byte("fourth\n"))
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+
+		assert.Len(t, logFiles(t, filepath.Join(dir, "logs")), 2)
+		assert.Equal(t, "third\nfourth\n", readLogFile(t, path))
+
+		clock.Add(24 * time.Hour)
+		r = &RotatingFile{Filename: path, Rotation: RotateDaily, UTC: true, Clock: clock.Now}
+		_, err = r.Write([]byte("fifth\n"))
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		assert.Len(t, logFiles(t, filepath.Join(dir, "logs")), 3)
+		assert.Equal(t, "fifth\n", readLogFile(t, path))
+	})
+
+	t.Run("RegularFile", func(t *testing.T) {
+		regular := filepath.Join(dir, "regular.log")
+		require.NoError(t, ioutil.WriteFile(regular, []byte("keep\n"), 0644))
+
+		r := &RotatingFile{Filename: regular, UTC: true, Clock: clock.Now}
+		_, err := r.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		assert.Equal(t, "keep\n", readLogFile(t, regular))
+	})
+}
+
+// TestRotatingFileCleanup tests the compression and removal of the rotated files.
+func TestRotatingFileCleanup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clock := &testClock{now: time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC)}
+	path := filepath.Join(dir, "app.log")
+
+	t.Run("MaxBackups", func(t *testing.T) {
+		r := &RotatingFile{Filename: path, MaxBackups: 2, Compress: true, UTC: true, Clock: clock.Now}
+		for i := 0; i < 5; i++ {
+			_, err := r.Write([]byte("line\n"))
+			require.NoError(t, err)
+			clock.Add(time.Second)
+			require.NoError(t, r.Rotate())
+		}
+		_, err := r.Write([]byte("current\n"))
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+
+		assert.Equal(t, []string{
+			"app-2019-07-01T12-30-03.000.log.gz",
+			"app-2019-07-01T12-30-04.000.log.gz",
+			"app-2019-07-01T12-30-05.000.log",
+		}, logFiles(t, dir))
+		assert.Equal(t, "line\n", readLogFile(t, filepath.Join(dir, "app-2019-07-01T12-30-04.000.log.gz")))
+		assert.Equal(t, "current\n", readLogFile(t, path))
+	})
+
+	require.NoError(t, os.RemoveAll(dir))
+
+	t.Run("MaxAge", func(t *testing.T) {
+		clock := &testClock{now: time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC)}
+		r := &RotatingFile{Filename: path, MaxAge: time.Hour, UTC: true, Clock: clock.Now}
+		_, err := r.Write([]byte("old\n"))
+		require.NoError(t, err)
+		clock.Add(2 * time.Hour)
+		require.NoError(t, r.Rotate())
+		_, err = r.Write([]byte("recent\n"))
+		require.NoError(t, err)
+		clock.Add(90 * time.Minute)
+		require.NoError(t, r.Rotate())
+		require.NoError(t, r.Close())
+
+		files := logFiles(t, dir)
+		assert.Equal(t, []string{"app-2019-07-01T14-30-00.000.log", "app-2019-07-01T16-00-00.000.log"}, files)
+		assert.Equal(t, "recent\n", readLogFile(t, filepath.Join(dir, files[0])))
+	})
+
+	require.NoError(t, os.RemoveAll(dir))
+
+	t.Run("MaxAgeSlowWriter", func(t *testing.T) {
+		clock := &testClock{now: time.Date(2019, 7, 1, 12, 30, 0, 0, time.UTC)}
+		r := &RotatingFile{Filename: path, MaxSize: 12, MaxAge: time.Hour, UTC: true, Clock: clock.Now}
+		_, err := r.Write([]byte("old\n"))
+		require.NoError(t, err)
+		clock.Add(48 * time.Hour)
+		_, err = r.Write([]byte("recent\n"))
+		require.NoError(t, err)
+		clock.Add(time.Second)
+		_, err = r.Write([]byte("rotated\n"))
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+
+		files := logFiles(t, dir)
+		assert.Equal(t, []string{"app-2019-07-01T12-30-00.000.log", "app-2019-07-03T12-30-01.000.log"}, files)
+		assert.Equal(t, "old\nrecent\n", readLogFile(t, filepath.Join(dir, files[0])))
+		assert.Equal(t, "rotated\n", readLogFile(t, path))
+	})
+}
+
+// TestRotatingFileLogger tests the RotatingFile used as the BasicLogger output.
+func TestRotatingFileLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r := &RotatingFile{Filename: filepath.Join(dir, "app.log"), MaxSize: 1024}
+	logger := NewBasicLogger(r, "", 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("message")
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, r.Close())
+
+	var lines int
+	for _, name := range logFiles(t, dir) {
+		data := readLogFile(t, filepath.Join(dir, name))
+		assert.True(t, len(data) <= 1024, name)
+		lines += strings.Count(data, "\n")
+	}
+	assert.Equal(t, 400, lines)
+}
@@ -0,0 +1,15 @@
+//go:build !js && !wasip1
+// +build !js,!wasip1
+
+package unilogger
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyHangup relays the SIGHUP signals to the channel 'c'.
+func notifyHangup(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGHUP)
+}
@@ -0,0 +1,11 @@
+//go:build js || wasip1
+// +build js wasip1
+
+package unilogger
+
+import (
+	"os"
+)
+
+// notifyHangup does nothing, as the SIGHUP signal is not supported on this system.
+func notifyHangup(c chan<- os.Signal) {}
@@ -0,0 +1,15 @@
+//go:build js || wasip1
+// +build js wasip1
+
+package unilogger
+
+import (
+	"testing"
+)
+
+// skipWithoutHangup skips the test, as the SIGHUP signal is not supported on this system.
+func skipWithoutHangup(t *testing.T) {
+	t.Skip("SIGHUP is not supported on this system")
+}
+
+func sendHangup(t *testing.T) {}
@@ -0,0 +1,27 @@
+//go:build !js && !wasip1
+// +build !js,!wasip1
+
+package unilogger
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// skipWithoutHangup skips the test if the SIGHUP signal could not be sent to the process.
+func skipWithoutHangup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not supported on windows")
+	}
+}
+
+// sendHangup sends the SIGHUP signal to the current process.
+func sendHangup(t *testing.T) {
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(syscall.SIGHUP))
+}
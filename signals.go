@@ -0,0 +1,58 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package unilogger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// HandleSignals starts changing the 'logger' level on the signals. The SIGUSR1 signal lowers
+// the level one step (i.e. INFO -> DEBUG -> DEBUG2 -> DEBUG3), so that the logger is more verbose,
+// and the SIGUSR2 signal raises it back. The steps stop at the DEBUG3 and INFO levels. The other levels
+// step to the nearest of these levels in the signal direction, i.e. SIGUSR1 sets WARNING to INFO.
+// The change is logged at the new level. Returns the function that stops handling the signals.
+// The signals are supported only on the unix systems, on the other systems the function does nothing.
+func HandleSignals(logger *BasicLogger) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				// the step is made from the level without the active elevations, as it is the level set.
+				level := logger.level.BaseLevel()
+				if sig == syscall.SIGUSR1 {
+					level = moreVerboseLevel(level)
+				} else {
+					level = lessVerboseLevel(level)
+				}
+				logger.level.SetLevel(level)
+
+				format := "Level changed to: '%s' by signal: '%s'"
+				logger.LogMessage(&Message{
+					id:    nextSequenceID(),
+					level: level,
+					time:  logger.clock(),
+					fmt:   &format,
+					args:  []interface{}{level, sig},
+				})
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package unilogger
+
+// HandleSignals is supported only on the unix systems. The returned stop function does nothing.
+func HandleSignals(logger *BasicLogger) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,80 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package unilogger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is the bytes.Buffer safe for the concurrent use.
+type syncBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.String()
+}
+
+// TestHandleSignals tests changing the logger level on the signals.
+func TestHandleSignals(t *testing.T) {
+	var buf syncBuffer
+	logger := NewBasicLogger(&buf, "", 0)
+	stop := HandleSignals(logger)
+	defer stop()
+
+	signal := func(sig syscall.Signal, expected Level) {
+		require.NoError(t, syscall.Kill(syscall.Getpid(), sig))
+		waitFor(t, func() bool { return logger.GetLevel() == expected })
+	}
+
+	signal(syscall.SIGUSR1, DEBUG)
+	waitFor(t, func() bool { return buf.String() != "" })
+	assert.Contains(t, buf.String(), "DEBUG|")
+	assert.Contains(t, buf.String(), "Level changed to: 'DEBUG' by signal: 'user defined signal 1'")
+
+	signal(syscall.SIGUSR1, DEBUG2)
+	signal(syscall.SIGUSR1, DEBUG3)
+	signal(syscall.SIGUSR2, DEBUG2)
+	signal(syscall.SIGUSR2, DEBUG)
+	signal(syscall.SIGUSR2, INFO)
+
+	// the steps stop at the INFO and DEBUG3 levels.
+	signal(syscall.SIGUSR2, INFO)
+	logger.SetLevel(DEBUG3)
+	signal(syscall.SIGUSR1, DEBUG3)
+
+	stop()
+	stop()
+}
+
+// TestHandleSignalsElevated tests that the level is stepped from the level without the elevations.
+func TestHandleSignalsElevated(t *testing.T) {
+	logger := NewBasicLogger(ioutil.Discard, "", 0)
+	stop := HandleSignals(logger)
+	defer stop()
+
+	logger.ElevateFor(DEBUG3, 300*time.Millisecond)
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	waitFor(t, func() bool { return logger.AtomicLevel().BaseLevel() == DEBUG })
+	assert.Equal(t, DEBUG3, logger.GetLevel())
+
+	waitFor(t, func() bool { return logger.GetLevel() == DEBUG })
+}
@@ -0,0 +1,193 @@
+//go:build go1.21
+// +build go1.21
+
+package unilogger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// LevelFromSlog maps the slog level into the logging Level.
+// The slog level is mapped to the level with the highest severity (see Level.Severity)
+// lower or equal to the slog level i.e.: slog.LevelInfo+1 is INFO, slog.LevelInfo+2 is NOTICE.
+// The levels lower than any known level are mapped to the lowest level.
+func LevelFromSlog(level slog.Level) Level {
+	return levelForSeverity(int(level))
+}
+
+// LevelToSlog maps the logging Level into the slog level using the level severity.
+// The PRINT and unknown levels are mapped to slog.LevelInfo.
+func LevelToSlog(level Level) slog.Level {
+	severity := level.Severity()
+	if severity >= PRINT.Severity() {
+		return slog.LevelInfo
+	}
+	return slog.Level(severity)
+}
+
+// SlogHandler is the slog.Handler that forwards the slog records to the LoggerWrapper.
+// The record attributes are passed as the message fields, where the keys of the grouped
+// attributes are prefixed with the group names joined with a dot i.e.: 'request.id'.
+// If the wrapped logger implements MessageLogger the fields are passed as structured fields,
+// otherwise they are written after the message text.
+type SlogHandler struct {
+	wrapper *LoggerWrapper
+	fields  []Field
+	group   string
+}
+
+var _ slog.Handler = &SlogHandler{}
+
+// NewSlogHandler creates new slog.Handler that forwards the records to the 'wrapper'.
+func NewSlogHandler(wrapper *LoggerWrapper) *SlogHandler {
+	return &SlogHandler{wrapper: wrapper}
+}
+
+// Enabled implements slog.Handler interface. The level is checked against the wrapper level filter
+// and if the wrapped logger implements LevelGetter against the logger level. Otherwise all levels are enabled.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if !h.wrapper.isAllowed(LevelFromSlog(level)) {
+		return false
+	}
+	if getter, ok := h.wrapper.logger.(LevelGetter); ok {
+		return getter.GetLevel().IsAllowed(LevelFromSlog(level))
+	}
+	return true
+}
+
+// Handle implements slog.Handler interface. The zero record time is passed as the zero message time,
+// which is omitted by the JSONFormatter and LogfmtFormatter.
+func (h *SlogHandler) Handle(_ context.Context, record slog.Record) error {
+	msg := &Message{
+		id:    nextSequenceID(),
+		level: LevelFromSlog(record.Level),
+		time:  record.Time,
+		args:  []interface{}{record.Message},
+		pc:    record.PC,
+	}
+
+	fields := make([]Field, len(h.fields), len(h.fields)+record.NumAttrs())
+	copy(fields, h.fields)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.group, attr)
+		return true
+	})
+	msg.fields = fields
+
+	h.wrapper.LogMessage(msg)
+	return nil
+}
+
+// WithAttrs implements slog.Handler interface.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := make([]Field, len(h.fields), len(h.fields)+len(attrs))
+	copy(fields, h.fields)
+	for _, attr := range attrs {
+		fields = appendSlogAttr(fields, h.group, attr)
+	}
+	return &SlogHandler{wrapper: h.wrapper, fields: fields, group: h.group}
+}
+
+// WithGroup implements slog.Handler interface.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{wrapper: h.wrapper, fields: h.fields, group: h.group + name + "."}
+}
+
+// appendSlogAttr appends the 'attr' to the fields. The group attributes are flattened
+// with their keys prefixed by the group name. Empty attributes are ignored.
+func appendSlogAttr(fields []Field, prefix string, attr slog.Attr) []Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+		for _, groupAttr := range attr.Value.Group() {
+			fields = appendSlogAttr(fields, groupPrefix, groupAttr)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: prefix + attr.Key, Value: attr.Value.Any()})
+}
+
+/**
+
+LoggerWrapper
+
+*/
+
+// wrapSlog sets the 'logger' as the wrapped logger if it is a *slog.Logger or slog.Handler.
+// The *slog.Logger is wrapped using its handler.
+func wrapSlog(wrapper *LoggerWrapper, logger interface{}) bool {
+	switch l := logger.(type) {
+	case *slog.Logger:
+		wrapper.logger = l.Handler()
+	case slog.Handler:
+		wrapper.logger = l
+	default:
+		return false
+	}
+	wrapper.currentLogger = 5
+	return true
+}
+
+// logSlog creates the slog record and passes it to the wrapped slog.Handler. If the 'format' is nil
+// the message is created in the fmt.Sprint manner or fmt.Sprintln if 'ln' is true.
+// The record source is set to the caller of the LoggerWrapper method.
+func (c *LoggerWrapper) logSlog(level Level, format *string, ln bool, args ...interface{}) {
+	handler := c.logger.(slog.Handler)
+	ctx := context.Background()
+	slogLevel := LevelToSlog(level)
+	if !handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	var msg string
+	switch {
+	case format != nil:
+		msg = fmt.Sprintf(*format, args...)
+	case ln:
+		msg = strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+	default:
+		msg = fmt.Sprint(args...)
+	}
+
+	// skip the runtime.Callers, logSlog and the LoggerWrapper method frames.
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), slogLevel, msg, pcs[0])
+	handler.Handle(ctx, record)
+}
+
+// logSlogMessage converts the message into the slog record and passes it to the wrapped slog.Handler.
+// The message fields are added as the record attributes.
+func (c *LoggerWrapper) logSlogMessage(m *Message) {
+	handler := c.logger.(slog.Handler)
+	ctx := context.Background()
+	slogLevel := LevelToSlog(m.level)
+	if !handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	record := slog.NewRecord(m.time, slogLevel, m.getMessage(), m.pc)
+	for _, field := range m.fields {
+		record.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+	handler.Handle(ctx, record)
+}
@@ -0,0 +1,168 @@
+//go:build go1.21
+// +build go1.21
+
+package unilogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLevelFromSlog tests the slog levels mapping.
+func TestLevelFromSlog(t *testing.T) {
+	assert.Equal(t, DEBUG3, LevelFromSlog(slog.LevelDebug-8))
+	assert.Equal(t, DEBUG2, LevelFromSlog(slog.LevelDebug-4))
+	assert.Equal(t, DEBUG, LevelFromSlog(slog.LevelDebug))
+	assert.Equal(t, INFO, LevelFromSlog(slog.LevelInfo))
+	assert.Equal(t, INFO, LevelFromSlog(slog.LevelInfo+1))
+	assert.Equal(t, NOTICE, LevelFromSlog(slog.LevelInfo+2))
+	assert.Equal(t, WARNING, LevelFromSlog(slog.LevelWarn))
+	assert.Equal(t, ERROR, LevelFromSlog(slog.LevelError))
+	assert.Equal(t, CRITICAL, LevelFromSlog(slog.LevelError+4))
+	assert.Equal(t, TRACE, LevelFromSlog(slog.LevelDebug-100))
+	assert.Equal(t, EMERGENCY, LevelFromSlog(slog.LevelError+100))
+
+	for _, level := range []Level{TRACE, DEBUG3, DEBUG2, DEBUG, INFO, NOTICE, WARNING, ERROR, CRITICAL, ALERT, EMERGENCY} {
+		assert.Equal(t, level, LevelFromSlog(LevelToSlog(level)))
+	}
+	assert.Equal(t, slog.LevelInfo, LevelToSlog(PRINT))
+}
+
+// TestSlogHandler tests the SlogHandler.
+func TestSlogHandler(t *testing.T) {
+	t.Run("MessageLogger", func(t *testing.T) {
+		var buf bytes.Buffer
+		basic := NewBasicLogger(&buf, "", log.Lshortfile)
+		logger := slog.New(NewSlogHandler(MustGetLoggerWrapper(basic)))
+
+		_, _, line, _ := runtime.Caller(0)
+		logger.With("service", "api").WithGroup("request").Warn("message", "id", 5, slog.Group("user", "name", "john"))
+		assert.Equal(t, fmt.Sprintf("slog_test.go:%d: WARNING|%04x: message service=api request.id=5 request.user.name=john\n", line+1, logSequenceID), buf.String())
+
+		buf.Reset()
+		logger.Debug("disabled")
+		assert.Empty(t, buf.String())
+		assert.False(t, logger.Enabled(context.Background(), slog.LevelDebug))
+	})
+
+	t.Run("StdLogger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(NewSlogHandler(MustGetLoggerWrapper(log.New(&buf, "", 0))))
+
+		logger.Error("failed", "err", "timeout", slog.Group("", "inline", true), slog.Attr{})
+		assert.Equal(t, "ERROR: failed err=timeout inline=true\n", buf.String())
+		assert.True(t, logger.Enabled(context.Background(), slog.LevelDebug-8))
+	})
+}
+
+// TestSlogHandlerContract tests the SlogHandler using the slogtest package.
+func TestSlogHandlerContract(t *testing.T) {
+	var buf bytes.Buffer
+	basic := NewBasicLogger(&buf, "", 0, WithJSONOutput())
+	basic.SetLevel(DEBUG3)
+	handler := NewSlogHandler(MustGetLoggerWrapper(basic))
+
+	results := func() []map[string]interface{} {
+		var records []map[string]interface{}
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			flat := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(line, &flat))
+
+			// the group attributes are flattened into the dotted keys.
+			record := map[string]interface{}{}
+			for key, value := range flat {
+				group := record
+				keys := strings.Split(key, ".")
+				for _, name := range keys[:len(keys)-1] {
+					if _, ok := group[name].(map[string]interface{}); !ok {
+						group[name] = map[string]interface{}{}
+					}
+					group = group[name].(map[string]interface{})
+				}
+				group[keys[len(keys)-1]] = value
+			}
+			records = append(records, record)
+		}
+		return records
+	}
+	assert.NoError(t, slogtest.TestHandler(handler, results))
+}
+
+// TestSlogLoggerWrapper tests the LoggerWrapper wrapping *slog.Logger and slog.Handler.
+func TestSlogLoggerWrapper(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			switch attr.Key {
+			case slog.TimeKey:
+				return slog.Attr{}
+			case slog.SourceKey:
+				source := attr.Value.Any().(*slog.Source)
+				if source.File == "" {
+					return slog.Attr{}
+				}
+				return slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", path.Base(source.File), source.Line))
+			}
+			return attr
+		},
+	})
+
+	t.Run("Logger", func(t *testing.T) {
+		wrapper, err := NewLoggerWrapper(slog.New(handler).With("service", "api"))
+		assert.NoError(t, err)
+
+		buf.Reset()
+		_, _, line, _ := runtime.Caller(0)
+		wrapper.Infof("message %d", 1)
+		assert.Equal(t, fmt.Sprintf("level=INFO source=slog_test.go:%d msg=\"message 1\" service=api\n", line+1), buf.String())
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		wrapper := MustGetLoggerWrapper(handler)
+
+		tests := []struct {
+			log   func()
+			level string
+			msg   string
+		}{
+			{func() { wrapper.Print("print", 1) }, "INFO", `msg=print1`},
+			{func() { wrapper.Println("println", 1) }, "INFO", `msg="println 1"`},
+			{func() { wrapper.Debug("debug") }, "DEBUG", `msg=debug`},
+			{func() { wrapper.Debugln("debug", "ln") }, "DEBUG", `msg="debug ln"`},
+			{func() { wrapper.Warningf("warning %s", "f") }, "WARN", `msg="warning f"`},
+			{func() { wrapper.Errorln("error") }, "ERROR", `msg=error`},
+		}
+		for _, test := range tests {
+			buf.Reset()
+			test.log()
+			assert.Contains(t, buf.String(), "level="+test.level+" source=slog_test.go:")
+			assert.Contains(t, buf.String(), test.msg+"\n")
+		}
+
+		buf.Reset()
+		assert.PanicsWithValue(t, "panic 1", func() { wrapper.Panicf("panic %d", 1) })
+		assert.Contains(t, buf.String(), `level=ERROR+4 source=slog_test.go:`)
+		assert.Contains(t, buf.String(), `msg="panic 1"`)
+	})
+
+	t.Run("LogMessage", func(t *testing.T) {
+		buf.Reset()
+		wrapper := MustGetLoggerWrapper(handler)
+		wrapper.LogMessage(NewMessage(WARNING, nil, "message").WithFields(Field{Key: "id", Value: 5}))
+		assert.Equal(t, "level=WARN msg=message id=5\n", buf.String())
+	})
+}
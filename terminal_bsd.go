@@ -0,0 +1,10 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package unilogger
+
+import (
+	"syscall"
+)
+
+const ioctlReadTermios = syscall.TIOCGETA
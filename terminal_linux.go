@@ -0,0 +1,7 @@
+package unilogger
+
+import (
+	"syscall"
+)
+
+const ioctlReadTermios = syscall.TCGETS
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd,!windows
+
+package unilogger
+
+// isTerminalFd is not supported on this system, thus the files are never treated as terminals.
+func isTerminalFd(fd uintptr) bool {
+	return false
+}
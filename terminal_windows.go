@@ -0,0 +1,11 @@
+package unilogger
+
+import (
+	"syscall"
+)
+
+// isTerminalFd checks if the handle 'fd' is a console.
+func isTerminalFd(fd uintptr) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
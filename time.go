@@ -0,0 +1,56 @@
+package unilogger
+
+import (
+	"time"
+)
+
+// TimeFormat defines how the message time is written by the formatters.
+// The zero value formats the time in RFC3339 with nanoseconds in the time zone
+// in which the message time was captured (local by default).
+type TimeFormat struct {
+	// Layout is the Go time layout. If empty the RFC3339 layout is used with the number of
+	// fractional second digits matching the 'Precision'.
+	Layout string
+	// Precision is the precision of the formatted time i.e.: time.Millisecond, time.Microsecond.
+	// The time is truncated to the given precision. Zero value doesn't truncate the time.
+	Precision time.Duration
+	// UTC defines if the time should be converted into UTC time zone.
+	UTC bool
+}
+
+// Format formats the time 't' based on the TimeFormat settings.
+func (f TimeFormat) Format(t time.Time) string {
+	if f.UTC {
+		t = t.UTC()
+	}
+	if f.Precision > 0 {
+		t = t.Truncate(f.Precision)
+	}
+	return t.Format(f.layout())
+}
+
+func (f TimeFormat) layout() string {
+	if f.Layout != "" {
+		return f.Layout
+	}
+	switch {
+	case f.Precision <= 0:
+		return time.RFC3339Nano
+	case f.Precision < time.Microsecond:
+		return "2006-01-02T15:04:05.000000000Z07:00"
+	case f.Precision < time.Millisecond:
+		return "2006-01-02T15:04:05.000000Z07:00"
+	case f.Precision < time.Second:
+		return "2006-01-02T15:04:05.000Z07:00"
+	default:
+		return time.RFC3339
+	}
+}
+
+// WithClock is the BasicLogger option that sets the function used to capture the message time.
+// By default time.Now is used. It might be used i.e. to provide fixed times in tests.
+func WithClock(clock func() time.Time) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
@@ -0,0 +1,68 @@
+package unilogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTimeFormat tests the TimeFormat.
+func TestTimeFormat(t *testing.T) {
+	tm := time.Date(2019, 7, 1, 12, 30, 15, 123456789, time.FixedZone("CEST", 2*60*60))
+
+	tests := []struct {
+		name     string
+		format   TimeFormat
+		expected string
+	}{
+		{"Default", TimeFormat{}, "2019-07-01T12:30:15.123456789+02:00"},
+		{"UTC", TimeFormat{UTC: true}, "2019-07-01T10:30:15.123456789Z"},
+		{"Milliseconds", TimeFormat{Precision: time.Millisecond}, "2019-07-01T12:30:15.123+02:00"},
+		{"Microseconds", TimeFormat{Precision: time.Microsecond, UTC: true}, "2019-07-01T10:30:15.123456Z"},
+		{"Nanoseconds", TimeFormat{Precision: time.Nanosecond}, "2019-07-01T12:30:15.123456789+02:00"},
+		{"Seconds", TimeFormat{Precision: time.Second}, "2019-07-01T12:30:15+02:00"},
+		{"Layout", TimeFormat{Layout: time.Kitchen, UTC: true}, "10:30AM"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.format.Format(tm))
+		})
+	}
+}
+
+// TestWithClock tests the message time captured using the BasicLogger clock.
+func TestWithClock(t *testing.T) {
+	tm := time.Date(2019, 7, 1, 12, 30, 15, 0, time.UTC)
+	clock := func() time.Time { return tm }
+
+	var buf bytes.Buffer
+	logger := NewBasicLogger(&buf, "", 0, WithClock(clock), WithFormatter(&JSONFormatter{Time: TimeFormat{Precision: time.Millisecond}}))
+	logger.With("a", 1).Info("message")
+
+	record := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "2019-07-01T12:30:15.000Z", record["time"])
+
+	t.Run("Text", func(t *testing.T) {
+		tm := time.Date(2019, 7, 1, 12, 30, 15, 123456789, time.FixedZone("CEST", 2*60*60))
+		clock := func() time.Time { return tm }
+
+		var buf bytes.Buffer
+		NewBasicLogger(&buf, "prefix ", log.LstdFlags, WithClock(clock)).Info("message")
+		assert.Equal(t, fmt.Sprintf("prefix 2019/07/01 12:30:15 INFO|%04x: message\n", logSequenceID), buf.String())
+
+		buf.Reset()
+		NewBasicLogger(&buf, "prefix ", log.LstdFlags|log.Lmicroseconds|log.LUTC|log.Lmsgprefix, WithClock(clock)).Info("message")
+		assert.Equal(t, fmt.Sprintf("2019/07/01 10:30:15.123456 prefix INFO|%04x: message\n", logSequenceID), buf.String())
+
+		buf.Reset()
+		NewBasicLogger(&buf, "", log.Ltime|log.Lshortfile, WithClock(clock)).Info("message")
+		assert.Regexp(t, fmt.Sprintf(`^12:30:15 time_test\.go:\d+: INFO\|%04x: message\n$`, logSequenceID), buf.String())
+	})
+}
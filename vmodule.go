@@ -0,0 +1,180 @@
+package unilogger
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// VModule is the set of the per package and per file level rules (similar to the glog 'vmodule' flag).
+// The rules are defined as comma separated 'pattern=LEVEL' pairs, i.e.: 'storage/*=DEBUG3,http/server.go=DEBUG'.
+// The pattern is matched using path.Match against the last path elements of the caller's:
+//	# file path - without the '.go' extension, i.e.: 'http/server' for the '/src/app/http/server.go' file.
+//	# package path - i.e.: 'storage/sql' for the 'github.com/org/app/storage/sql' package.
+// The number of the compared elements is equal to the number of elements in the pattern.
+// The '.go' extension of the pattern is ignored. The first matching rule defines the level
+// for the call site, which replaces the logger level. The rule matched for the call site is cached,
+// so that the caller is resolved only once.
+type VModule struct {
+	state atomic.Value
+	// hasRules is set to 1 if there are any rules, so that the loggers could skip
+	// resolving their callers without loading the state.
+	hasRules int32
+}
+
+type vmoduleState struct {
+	rules    []vmoduleRule
+	minLevel Level
+
+	// cache contains the levels of the resolved call sites keyed by their program counters,
+	// so that it is read without locks and allocations.
+	cache sync.Map
+}
+
+type vmoduleRule struct {
+	pattern  string
+	elements int
+	level    Level
+}
+
+// vmoduleNoMatch is the cached value for the call sites that doesn't match any rule.
+const vmoduleNoMatch = UNKNOWN
+
+// NewVModule creates new VModule with the rules parsed from the 'spec'.
+func NewVModule(spec string) (*VModule, error) {
+	v := &VModule{}
+	if err := v.Set(spec); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// MustVModule creates new VModule with the rules parsed from the 'spec'. Panics if the spec is not valid.
+func MustVModule(spec string) *VModule {
+	v, err := NewVModule(spec)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Set atomically replaces the rules with the ones parsed from the 'spec'.
+// An empty spec removes all the rules. If the spec is not valid the rules are not changed.
+func (v *VModule) Set(spec string) error {
+	state := &vmoduleState{minLevel: UNKNOWN}
+	for _, rule := range strings.Split(spec, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		i := strings.LastIndexByte(rule, '=')
+		if i <= 0 {
+			return fmt.Errorf("invalid vmodule rule: '%s'", rule)
+		}
+		pattern := strings.TrimSuffix(strings.Trim(rule[:i], "/"), ".go")
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("invalid vmodule pattern: '%s'", rule[:i])
+		}
+		level, err := parseLevelName(strings.TrimSpace(rule[i+1:]))
+		if err != nil {
+			return fmt.Errorf("invalid vmodule rule: '%s': %v", rule, err)
+		}
+		state.rules = append(state.rules, vmoduleRule{pattern: pattern, elements: strings.Count(pattern, "/") + 1, level: level})
+		if level.Severity() < state.minLevel.Severity() {
+			state.minLevel = level
+		}
+	}
+	v.state.Store(state)
+	if len(state.rules) > 0 {
+		atomic.StoreInt32(&v.hasRules, 1)
+	} else {
+		atomic.StoreInt32(&v.hasRules, 0)
+	}
+	return nil
+}
+
+// String implements fmt.Stringer interface. It returns the rules in the spec form.
+func (v *VModule) String() string {
+	state := v.load()
+	rules := make([]string, len(state.rules))
+	for i, rule := range state.rules {
+		rules[i] = rule.pattern + "=" + rule.level.String()
+	}
+	return strings.Join(rules, ",")
+}
+
+// LevelFor gets the level for the call site with the program counter 'pc'.
+// Returns false if none of the rules matches the call site.
+func (v *VModule) LevelFor(pc uintptr) (Level, bool) {
+	state := v.load()
+	if len(state.rules) == 0 || pc == 0 {
+		return UNKNOWN, false
+	}
+
+	if cached, ok := state.cache.Load(pc); ok {
+		level := cached.(Level)
+		return level, level != vmoduleNoMatch
+	}
+
+	level := vmoduleNoMatch
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	file := strings.TrimSuffix(frame.File, ".go")
+	pkg := functionPackage(frame.Function)
+	for _, rule := range state.rules {
+		if rule.matches(file) || rule.matches(pkg) {
+			level = rule.level
+			break
+		}
+	}
+	state.cache.Store(pc, level)
+	return level, level != vmoduleNoMatch
+}
+
+// mayEnable checks if any of the rules might enable the 'level'.
+func (v *VModule) mayEnable(level Level) bool {
+	if atomic.LoadInt32(&v.hasRules) == 0 {
+		return false
+	}
+	return v.load().minLevel.IsAllowed(level)
+}
+
+func (v *VModule) load() *vmoduleState {
+	state, _ := v.state.Load().(*vmoduleState)
+	if state == nil {
+		return &vmoduleState{minLevel: UNKNOWN}
+	}
+	return state
+}
+
+// matches checks if the last elements of the 'name' path matches the rule pattern.
+func (r vmoduleRule) matches(name string) bool {
+	elements := strings.Split(name, "/")
+	if name == "" || len(elements) < r.elements {
+		return false
+	}
+	ok, _ := path.Match(r.pattern, strings.Join(elements[len(elements)-r.elements:], "/"))
+	return ok
+}
+
+// functionPackage gets the package path of the function with the full 'name'
+// i.e.: 'github.com/org/app/storage' for the 'github.com/org/app/storage.(*DB).Query'.
+func functionPackage(name string) string {
+	slash := strings.LastIndexByte(name, '/')
+	if dot := strings.IndexByte(name[slash+1:], '.'); dot != -1 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// WithVModule is the BasicLogger option that sets the per package and per file level rules.
+// The rules could be changed later using VModule.Set method. The call site is resolved using
+// the logger output depth, thus for the subloggers it is the caller of the function calling
+// the sublogger methods (see BasicLogger.SubLogger).
+func WithVModule(vmodule *VModule) Option {
+	return func(o *options) {
+		o.vmodule = vmodule
+	}
+}
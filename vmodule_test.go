@@ -0,0 +1,99 @@
+package unilogger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVModule tests the VModule rules.
+func TestVModule(t *testing.T) {
+	t.Run("Parse", func(t *testing.T) {
+		vmodule, err := NewVModule(" storage/*=DEBUG3, http/server.go=debug,")
+		require.NoError(t, err)
+		assert.Equal(t, "storage/*=DEBUG3,http/server=DEBUG", vmodule.String())
+
+		invalid := []string{"storage", "=DEBUG", "storage=unknown", "[=DEBUG"}
+		for _, spec := range invalid {
+			_, err := NewVModule(spec)
+			assert.Error(t, err, spec)
+		}
+		assert.Panics(t, func() { MustVModule("storage") })
+
+		assert.Error(t, vmodule.Set("storage"))
+		assert.Equal(t, "storage/*=DEBUG3,http/server=DEBUG", vmodule.String())
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		rule := vmoduleRule{pattern: "storage/*", elements: 2}
+		assert.True(t, rule.matches("/src/app/storage/db"))
+		assert.True(t, rule.matches("github.com/org/app/storage/sql"))
+		assert.False(t, rule.matches("github.com/org/app/storage"))
+		assert.False(t, rule.matches("db"))
+
+		assert.Equal(t, "github.com/org/app/storage", functionPackage("github.com/org/app/storage.(*DB).Query"))
+		assert.Equal(t, "main", functionPackage("main.main"))
+	})
+
+	t.Run("LevelFor", func(t *testing.T) {
+		pc, _, _, _ := runtime.Caller(0)
+		vmodule := MustVModule("other=ERROR,*/vmodule_test.go=DEBUG2")
+
+		level, ok := vmodule.LevelFor(pc)
+		assert.True(t, ok)
+		assert.Equal(t, DEBUG2, level)
+
+		// cached call site.
+		level, ok = vmodule.LevelFor(pc)
+		assert.True(t, ok)
+		assert.Equal(t, DEBUG2, level)
+
+		require.NoError(t, vmodule.Set("other=ERROR"))
+		_, ok = vmodule.LevelFor(pc)
+		assert.False(t, ok)
+	})
+
+	t.Run("Logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		vmodule := MustVModule("vmodule_test=DEBUG3")
+		logger := NewBasicLogger(&buf, "", 0, WithVModule(vmodule))
+
+		logger.Debug3("enabled")
+		assert.Contains(t, buf.String(), "enabled")
+
+		buf.Reset()
+		require.NoError(t, vmodule.Set("vmodule_test=ERROR"))
+		logger.Warning("disabled")
+		assert.Empty(t, buf.String())
+
+		require.NoError(t, vmodule.Set(""))
+		logger.Warning("logger level")
+		assert.Contains(t, buf.String(), "logger level")
+	})
+
+	t.Run("SubLogger", func(t *testing.T) {
+		var buf bytes.Buffer
+		vmodule := MustVModule("vmodule_test=DEBUG")
+		sub := NewBasicLogger(&buf, "", 0, WithVModule(vmodule)).SubLogger()
+		// the sublogger call site is the caller of the function calling its methods.
+		debug := func(msg string) { sub.Debug(msg) }
+
+		debug("enabled")
+		assert.Contains(t, buf.String(), "enabled")
+
+		buf.Reset()
+		sub.Debug("direct")
+		assert.Empty(t, buf.String())
+	})
+}
+
+// TestVModuleDisabledAllocs tests that the logs disabled by the vmodule rules don't allocate.
+func TestVModuleDisabledAllocs(t *testing.T) {
+	logger := NewBasicLogger(ioutil.Discard, "", 0, WithVModule(MustVModule("other/*=DEBUG")))
+	allocs := testing.AllocsPerRun(100, func() { logger.Debug("disabled") })
+	assert.Equal(t, float64(0), allocs)
+}
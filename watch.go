@@ -0,0 +1,140 @@
+package unilogger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// ConfigWatcher reloads the config of the Pipeline from the file when the process receives
+// the SIGHUP signal or when the file modification time or size changes. The invalid configs
+// are rejected and reported, while the previous config keeps running.
+// The SIGHUP signal is not supported on js and wasip1.
+type ConfigWatcher struct {
+	path     string
+	pipeline *Pipeline
+	onError  func(err error)
+
+	lock    sync.Mutex
+	modTime time.Time
+	size    int64
+
+	signals  chan os.Signal
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// WatchConfigFile loads the config from the file at 'path', builds the Pipeline and starts watching
+// the file. The file is polled every 'interval' - if it is not positive, the config is reloaded only
+// on SIGHUP signal or using the Reload method. The reload errors are passed to the 'onError' function.
+// If 'onError' is nil, they are written to the standard error output.
+func WatchConfigFile(path string, interval time.Duration, onError func(err error)) (*ConfigWatcher, error) {
+	w := &ConfigWatcher{
+		path:    path,
+		onError: onError,
+		signals: make(chan os.Signal, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if w.onError == nil {
+		w.onError = func(err error) {
+			fmt.Fprintf(os.Stderr, "unilogger: %v\n", err)
+		}
+	}
+
+	config, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.pipeline, err = Build(*config)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyHangup(w.signals)
+	go w.watch(interval)
+	return w, nil
+}
+
+// Pipeline gets the watched pipeline.
+func (w *ConfigWatcher) Pipeline() *Pipeline {
+	return w.pipeline
+}
+
+// Reload reads the config file and applies it to the pipeline.
+// If the config is not valid, the error is returned and the previous config keeps running.
+func (w *ConfigWatcher) Reload() error {
+	config, err := w.load()
+	if err != nil {
+		return err
+	}
+	if err = w.pipeline.Apply(*config); err != nil {
+		return fmt.Errorf("config: '%s' rejected: %v", w.path, err)
+	}
+	return nil
+}
+
+// Stop stops watching the config file. The pipeline is not closed.
+func (w *ConfigWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		signal.Stop(w.signals)
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// load reads the config file and stores its modification time and size.
+func (w *ConfigWatcher) load() (*Config, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if info, err := os.Stat(w.path); err == nil {
+		w.modTime, w.size = info.ModTime(), info.Size()
+	}
+	config, err := LoadConfigFile(w.path)
+	if err != nil {
+		return nil, fmt.Errorf("config: '%s' rejected: %v", w.path, err)
+	}
+	return config, nil
+}
+
+// changed checks if the config file modification time or size differs from the loaded one.
+func (w *ConfigWatcher) changed() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return !info.ModTime().Equal(w.modTime) || info.Size() != w.size
+}
+
+func (w *ConfigWatcher) watch(interval time.Duration) {
+	defer close(w.done)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.signals:
+		case <-tick:
+			if !w.changed() {
+				continue
+			}
+		}
+		if err := w.Reload(); err != nil {
+			w.onError(err)
+		}
+	}
+}
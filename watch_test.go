@@ -0,0 +1,97 @@
+package unilogger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes the config 'data' and moves the file modification time forward,
+// so that the change is detected regardless of the file system time resolution.
+// The data is written to a temporary file moved into the 'path', so that the watcher
+// sees the new content and modification time as a single change.
+func writeConfigFile(t *testing.T, path, data string, modTime time.Time) {
+	tmp := path + ".tmp"
+	require.NoError(t, ioutil.WriteFile(tmp, []byte(data), 0644))
+	require.NoError(t, os.Chtimes(tmp, modTime, modTime))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+// TestWatchConfigFile tests reloading the config when the file changes.
+func TestWatchConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unilogger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	now := time.Now()
+	writeConfigFile(t, path, `{"loggers": {"app": {"level": "info"}}}`, now)
+
+	errs := make(chan error, 10)
+	w, err := WatchConfigFile(path, 10*time.Millisecond, func(err error) { errs <- err })
+	require.NoError(t, err)
+	defer w.Stop()
+	defer w.Pipeline().Close()
+
+	app := w.Pipeline().Logger("app")
+	assert.Equal(t, INFO, app.GetLevel())
+
+	t.Run("Changed", func(t *testing.T) {
+		writeConfigFile(t, path, `{"loggers": {"app": {"level": "debug2"}}}`, now.Add(time.Second))
+		waitFor(t, func() bool { return app.GetLevel() == DEBUG2 })
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		writeConfigFile(t, path, `{"loggers": {"app": {"level": "verbose"}}}`, now.Add(2*time.Second))
+		select {
+		case err := <-errs:
+			assert.Contains(t, err.Error(), "rejected")
+		case <-time.After(time.Second):
+			t.Fatal("reload error not reported")
+		}
+		assert.Equal(t, DEBUG2, app.GetLevel())
+
+		writeConfigFile(t, path, `{"loggers": {"app": {"sinks": ["missing"]}}}`, now.Add(3*time.Second))
+		select {
+		case err := <-errs:
+			assert.Contains(t, err.Error(), "unknown sink: 'missing'")
+		case <-time.After(time.Second):
+			t.Fatal("reload error not reported")
+		}
+		assert.Equal(t, DEBUG2, app.GetLevel())
+	})
+
+	t.Run("Signal", func(t *testing.T) {
+		skipWithoutHangup(t)
+		w.Stop()
+
+		// the stopped watcher doesn't poll the file, thus it is reloaded only on demand.
+		writeConfigFile(t, path, `{"loggers": {"app": {"level": "error"}}}`, now.Add(4*time.Second))
+		assert.Equal(t, DEBUG2, app.GetLevel())
+		require.NoError(t, w.Reload())
+		assert.Equal(t, ERROR, app.GetLevel())
+
+		signaled, err := WatchConfigFile(path, 0, func(err error) { errs <- err })
+		require.NoError(t, err)
+		defer signaled.Stop()
+		defer signaled.Pipeline().Close()
+
+		logger := signaled.Pipeline().Logger("app")
+		writeConfigFile(t, path, `{"loggers": {"app": {"level": "warning"}}}`, now.Add(5*time.Second))
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, ERROR, logger.GetLevel())
+
+		sendHangup(t)
+		waitFor(t, func() bool { return logger.GetLevel() == WARNING })
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := WatchConfigFile(filepath.Join(dir, "missing.json"), 0, nil)
+		assert.Error(t, err)
+	})
+}
@@ -3,6 +3,9 @@ package unilogger
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 )
 
 // LoggerWrapper is wrapper around any third-party logger that implements any of
@@ -11,14 +14,17 @@ import (
 //	# ShortLeveledLogger
 //	# LeveledLogger
 //	# StdLogger
+//	# *slog.Logger or slog.Handler (go1.21 or later)
 // By wrapping the logger it implements ExtendedLeveledLogger.
 // For loggers that implements only StdLogger, LoggerWrapper tries to virtualize
 // leveled logger behaviour. It simply adds level name before logging message.
 // If a logger implements LeveledLogger that doesn't have specific log line '****ln()' methods,
 // it uses default non 'ln' functions - i.e. instead 'Infoln' uses 'Info'.
+// The messages might be additionally filtered by the level filter (see WithLevelFilter).
 type LoggerWrapper struct {
 	logger        interface{}
 	currentLogger int
+	filter        LevelFilter
 }
 
 // NewLoggerWrapper creates a LoggerWrapper wrapper over provided 'logger' argument
@@ -28,6 +34,7 @@ type LoggerWrapper struct {
 //	# ShortLeveledLogger
 //	# LeveledLogger
 //	# StdLogger
+//	# *slog.Logger or slog.Handler (go1.21 or later)
 // if logger doesn't implement an interface it tries to check the next in hierarchy.
 // If it doesn't implement any of known logging interfaces the function returns error.
 func NewLoggerWrapper(logger interface{}) (*LoggerWrapper, error) {
@@ -41,6 +48,7 @@ func NewLoggerWrapper(logger interface{}) (*LoggerWrapper, error) {
 //	# ShortLeveledLogger
 //	# LeveledLogger
 //	# StdLogger
+//	# *slog.Logger or slog.Handler (go1.21 or later)
 // if logger doesn't implement an interface it tries to check the next in hierarchy.
 // If it doesn't implement any of known logging interfaces the function panics.
 func MustGetLoggerWrapper(logger interface{}) *LoggerWrapper {
@@ -79,6 +87,10 @@ func newLoggerWrapper(logger interface{}) (*LoggerWrapper, error) {
 		return wrapper, nil
 	}
 
+	if wrapSlog(wrapper, logger) {
+		return wrapper, nil
+	}
+
 	err = errors.New("Provided logger doesn't implement any known interfaces")
 	return nil, err
 }
@@ -87,6 +99,9 @@ func newLoggerWrapper(logger interface{}) (*LoggerWrapper, error) {
 // Arguments are handled in the manner of log.Print for StdLogger and
 // Extended LeveledLogger as well as log.Info for LeveledLogger
 func (c *LoggerWrapper) Print(args ...interface{}) {
+	if !c.isAllowed(PRINT) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -100,6 +115,8 @@ func (c *LoggerWrapper) Print(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Print(args...)
+	case 5:
+		c.logSlog(PRINT, nil, false, args...)
 	default:
 	}
 }
@@ -108,6 +125,9 @@ func (c *LoggerWrapper) Print(args ...interface{}) {
 // Arguments are handled in the manner of log.Printf for StdLogger and
 // Extended LeveledLogger as well as log.Infof for LeveledLogger
 func (c *LoggerWrapper) Printf(format string, args ...interface{}) {
+	if !c.isAllowed(PRINT) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -121,6 +141,8 @@ func (c *LoggerWrapper) Printf(format string, args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Printf(format, args...)
+	case 5:
+		c.logSlog(PRINT, &format, false, args...)
 	default:
 	}
 }
@@ -129,6 +151,9 @@ func (c *LoggerWrapper) Printf(format string, args ...interface{}) {
 // Arguments are handled in the manner of log.Println for StdLogger and
 // Extended LeveledLogger as well as log.Info for LeveledLogger
 func (c *LoggerWrapper) Println(args ...interface{}) {
+	if !c.isAllowed(PRINT) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -142,6 +167,8 @@ func (c *LoggerWrapper) Println(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Println(args...)
+	case 5:
+		c.logSlog(PRINT, nil, true, args...)
 	default:
 
 	}
@@ -151,6 +178,9 @@ func (c *LoggerWrapper) Println(args ...interface{}) {
 // Arguments are handled in the manner of log.Print for StdLogger,
 // log.Debug for ExtendedLeveledLogger and LeveledLogger.
 func (c *LoggerWrapper) Debug(args ...interface{}) {
+	if !c.isAllowed(DEBUG) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -165,6 +195,8 @@ func (c *LoggerWrapper) Debug(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Debug(args...)
+	case 5:
+		c.logSlog(DEBUG, nil, false, args...)
 	default:
 	}
 }
@@ -173,6 +205,9 @@ func (c *LoggerWrapper) Debug(args ...interface{}) {
 // Arguments are handled in the manner of log.Printf for StdLogger,
 // log.Debugf for ExtendedLeveledLogger, ShortLeveledLogger and LeveledLogger.
 func (c *LoggerWrapper) Debugf(format string, args ...interface{}) {
+	if !c.isAllowed(DEBUG) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -187,6 +222,8 @@ func (c *LoggerWrapper) Debugf(format string, args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Debugf(format, args...)
+	case 5:
+		c.logSlog(DEBUG, &format, false, args...)
 	default:
 	}
 }
@@ -195,6 +232,9 @@ func (c *LoggerWrapper) Debugf(format string, args ...interface{}) {
 // Arguments are handled in the manner of log.Println for StdLogger,
 // log.Debugln for ExtendedLeveledLogger and log.Debug for LeveledLogger and ShortLeveledLogger.
 func (c *LoggerWrapper) Debugln(args ...interface{}) {
+	if !c.isAllowed(DEBUG) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -209,6 +249,8 @@ func (c *LoggerWrapper) Debugln(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Debugln(args...)
+	case 5:
+		c.logSlog(DEBUG, nil, true, args...)
 	default:
 	}
 
@@ -218,6 +260,9 @@ func (c *LoggerWrapper) Debugln(args ...interface{}) {
 // Arguments are handled in the manner of log.Print for StdLogger,
 // log.Info for ExtendedLeveledLogger, ShortLeveledLogger and LeveledLogger.
 func (c *LoggerWrapper) Info(args ...interface{}) {
+	if !c.isAllowed(INFO) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -232,6 +277,8 @@ func (c *LoggerWrapper) Info(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Info(args...)
+	case 5:
+		c.logSlog(INFO, nil, false, args...)
 	default:
 	}
 
@@ -241,6 +288,9 @@ func (c *LoggerWrapper) Info(args ...interface{}) {
 // Arguments are handled in the manner of log.Printf for StdLogger,
 // log.Infof for ExtendedLeveledLogger, ShortLeveledLogger and LeveledLogger.
 func (c *LoggerWrapper) Infof(format string, args ...interface{}) {
+	if !c.isAllowed(INFO) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -255,6 +305,8 @@ func (c *LoggerWrapper) Infof(format string, args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Infof(format, args...)
+	case 5:
+		c.logSlog(INFO, &format, false, args...)
 	default:
 	}
 }
@@ -263,6 +315,9 @@ func (c *LoggerWrapper) Infof(format string, args ...interface{}) {
 // Arguments are handled in the manner of log.Println for StdLogger,
 // log.Infoln for ExtendedLeveledLogger and log.Info for LeveledLogger and ShortLeveledLogger.
 func (c *LoggerWrapper) Infoln(args ...interface{}) {
+	if !c.isAllowed(INFO) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -277,6 +332,8 @@ func (c *LoggerWrapper) Infoln(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Infoln(args...)
+	case 5:
+		c.logSlog(INFO, nil, true, args...)
 	default:
 	}
 }
@@ -286,6 +343,9 @@ func (c *LoggerWrapper) Infoln(args ...interface{}) {
 // log.Warning for ExtendedLeveledLogger, LeveledLogger and
 // log.Warn for ShortLeveledLogger.
 func (c *LoggerWrapper) Warning(args ...interface{}) {
+	if !c.isAllowed(WARNING) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -300,6 +360,8 @@ func (c *LoggerWrapper) Warning(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Warning(args...)
+	case 5:
+		c.logSlog(WARNING, nil, false, args...)
 	default:
 	}
 }
@@ -308,6 +370,9 @@ func (c *LoggerWrapper) Warning(args ...interface{}) {
 // Arguments are handled in the manner of log.Printf for StdLogger,
 // log.Warningf for ExtendedLeveledLogger, LeveledLogger and log.Warnf for ShortLeveledLogger.
 func (c *LoggerWrapper) Warningf(format string, args ...interface{}) {
+	if !c.isAllowed(WARNING) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -322,6 +387,8 @@ func (c *LoggerWrapper) Warningf(format string, args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Warningf(format, args...)
+	case 5:
+		c.logSlog(WARNING, &format, false, args...)
 	default:
 	}
 }
@@ -331,6 +398,9 @@ func (c *LoggerWrapper) Warningf(format string, args ...interface{}) {
 // log.Warningln for ExtendedLeveledLogger, log.Warning for LeveledLogger
 // and log.Warn for ShortLeveledLogger.
 func (c *LoggerWrapper) Warningln(args ...interface{}) {
+	if !c.isAllowed(WARNING) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -345,6 +415,8 @@ func (c *LoggerWrapper) Warningln(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Warningln(args...)
+	case 5:
+		c.logSlog(WARNING, nil, true, args...)
 	default:
 	}
 }
@@ -353,6 +425,9 @@ func (c *LoggerWrapper) Warningln(args ...interface{}) {
 // Arguments are handled in the manner of log.Print for StdLogger,
 // log.Error for ExtendedLeveledLogger, LeveledLogger and ShortLeveledLogger.
 func (c *LoggerWrapper) Error(args ...interface{}) {
+	if !c.isAllowed(ERROR) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -367,6 +442,8 @@ func (c *LoggerWrapper) Error(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Error(args...)
+	case 5:
+		c.logSlog(ERROR, nil, false, args...)
 	default:
 	}
 }
@@ -375,6 +452,9 @@ func (c *LoggerWrapper) Error(args ...interface{}) {
 // Arguments are handled in the manner of log.Printf for StdLogger,
 // log.Errorf for ExtendedLeveledLogger, LeveledLogger and ShortLeveledLogger.
 func (c *LoggerWrapper) Errorf(format string, args ...interface{}) {
+	if !c.isAllowed(ERROR) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -389,6 +469,8 @@ func (c *LoggerWrapper) Errorf(format string, args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Errorf(format, args...)
+	case 5:
+		c.logSlog(ERROR, &format, false, args...)
 	default:
 	}
 }
@@ -397,6 +479,9 @@ func (c *LoggerWrapper) Errorf(format string, args ...interface{}) {
 // Arguments are handled in the manner of log.Println for StdLogger,
 // log.Debugln for ExtendedLeveledLogger and log.Error for LeveledLogger and ShortLeveledLogger.
 func (c *LoggerWrapper) Errorln(args ...interface{}) {
+	if !c.isAllowed(ERROR) {
+		return
+	}
 	switch c.currentLogger {
 	case 1:
 		log := c.logger.(StdLogger)
@@ -411,6 +496,8 @@ func (c *LoggerWrapper) Errorln(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Errorln(args...)
+	case 5:
+		c.logSlog(ERROR, nil, true, args...)
 	default:
 	}
 }
@@ -433,6 +520,9 @@ func (c *LoggerWrapper) Fatal(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Fatal(args...)
+	case 5:
+		c.logSlog(CRITICAL, nil, false, args...)
+		os.Exit(1)
 	default:
 	}
 }
@@ -455,6 +545,9 @@ func (c *LoggerWrapper) Fatalf(format string, args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Fatalf(format, args...)
+	case 5:
+		c.logSlog(CRITICAL, &format, false, args...)
+		os.Exit(1)
 	default:
 	}
 }
@@ -477,6 +570,9 @@ func (c *LoggerWrapper) Fatalln(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Fatalln(args...)
+	case 5:
+		c.logSlog(CRITICAL, nil, true, args...)
+		os.Exit(1)
 	default:
 	}
 }
@@ -499,6 +595,9 @@ func (c *LoggerWrapper) Panic(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Panic(args...)
+	case 5:
+		c.logSlog(CRITICAL, nil, false, args...)
+		panic(fmt.Sprint(args...))
 	default:
 	}
 }
@@ -521,6 +620,9 @@ func (c *LoggerWrapper) Panicf(format string, args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Panicf(format, args...)
+	case 5:
+		c.logSlog(CRITICAL, &format, false, args...)
+		panic(fmt.Sprintf(format, args...))
 	default:
 	}
 }
@@ -543,7 +645,108 @@ func (c *LoggerWrapper) Panicln(args ...interface{}) {
 	case 4:
 		log := c.logger.(ExtendedLeveledLogger)
 		log.Panicln(args...)
+	case 5:
+		c.logSlog(CRITICAL, nil, true, args...)
+		panic(fmt.Sprintln(args...))
+	default:
+	}
+}
+
+var _ LevelSetter = &LoggerWrapper{}
+
+// SetLevel sets the level of the wrapped logger if it implements LevelSetter interface.
+// Otherwise the function does nothing.
+func (c *LoggerWrapper) SetLevel(level Level) {
+	if setter, ok := c.logger.(LevelSetter); ok {
+		setter.SetLevel(level)
+	}
+}
+
+var _ LevelElevator = &LoggerWrapper{}
+
+// ElevateFor temporarily lowers the level of the wrapped logger if it implements LevelElevator interface.
+// Otherwise the function does nothing.
+func (c *LoggerWrapper) ElevateFor(level Level, d time.Duration) {
+	if elevator, ok := c.logger.(LevelElevator); ok {
+		elevator.ElevateFor(level, d)
+	}
+}
+
+var _ LevelGetter = &LoggerWrapper{}
+
+// GetLevel gets the level of the wrapped logger if it implements LevelGetter interface.
+// Otherwise the function returns UNKNOWN level.
+func (c *LoggerWrapper) GetLevel() Level {
+	if getter, ok := c.logger.(LevelGetter); ok {
+		return getter.GetLevel()
+	}
+	return UNKNOWN
+}
+
+// WithLevelFilter creates a copy of the wrapper that logs only the messages with the levels
+// allowed by the 'filter', i.e. NewLevelSet(DEBUG2, DEBUG) or NewLevelRange(DEBUG3, DEBUG).
+// The Print methods use the PRINT level. The Fatal and Panic methods are not filtered,
+// so that they always exit or panic. A nil 'filter' removes the filtering.
+func (c *LoggerWrapper) WithLevelFilter(filter LevelFilter) *LoggerWrapper {
+	wrapper := *c
+	wrapper.filter = filter
+	return &wrapper
+}
+
+// isAllowed checks if the 'level' is allowed by the level filter.
+func (c *LoggerWrapper) isAllowed(level Level) bool {
+	return c.filter == nil || c.filter.IsAllowed(level)
+}
+
+var _ MessageLogger = &LoggerWrapper{}
+
+// LogMessage logs the message 'm'. If the wrapped logger implements MessageLogger
+// the message is passed directly to it. Otherwise the message fields are written after
+// the message text in a 'key=value' form and the text is logged using the method
+// matching the message level severity. The levels lower than INFO are logged as DEBUG,
+// NOTICE as INFO and the levels higher than ERROR as ERROR, so that the function never exits nor panics.
+// The StdLogger messages are prefixed with the message level name, including the custom levels.
+func (c *LoggerWrapper) LogMessage(m *Message) {
+	if !c.isAllowed(m.level) {
+		return
+	}
+	if l, ok := c.logger.(MessageLogger); ok {
+		l.LogMessage(m)
+		return
+	}
+	if c.currentLogger == 5 {
+		c.logSlogMessage(m)
+		return
+	}
+
+	msg := m.getMessage()
+	if len(m.fields) != 0 {
+		b := strings.Builder{}
+		b.WriteString(msg)
+		b.WriteRune(' ')
+		writeFields(&b, m.fields)
+		msg = b.String()
+	}
+
+	if c.currentLogger == 1 && m.level != PRINT {
+		c.logger.(StdLogger).Print(buildLeveled(m.level, nil, msg)...)
+		return
+	}
+
+	// the message is already filtered by its level.
+	unfiltered := *c
+	unfiltered.filter = nil
+	switch severity := m.level.Severity(); {
+	case severity >= PRINT.Severity():
+		unfiltered.Print(msg)
+	case severity < INFO.Severity():
+		unfiltered.Debug(msg)
+	case severity < WARNING.Severity():
+		unfiltered.Info(msg)
+	case severity < ERROR.Severity():
+		unfiltered.Warning(msg)
 	default:
+		unfiltered.Error(msg)
 	}
 }
 